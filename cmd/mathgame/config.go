@@ -0,0 +1,236 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"math-game/internal/levels"
+	"math-game/internal/problems"
+	"math-game/internal/ui"
+)
+
+// settingFlags holds the command-line flag for each generator setting,
+// keyed by problem type and then setting key
+var settingFlags = map[problems.ProblemType]map[string]*int{}
+
+// registerSettingFlags adds a flag such as -addition.maxDigits for every
+// setting of every registered problem type
+func registerSettingFlags() {
+	for _, def := range problems.Definitions() {
+		settingFlags[def.Type] = map[string]*int{}
+		for _, s := range def.Settings {
+			name := fmt.Sprintf("%s.%s", def.Type, s.Key)
+			if s.Tables {
+				value := tablesFlag{mask: new(int)}
+				flag.Var(value, name, fmt.Sprintf("%s: %s, e.g. 6,7,8 or 2-5", def.Name, s.Description))
+				settingFlags[def.Type][s.Key] = value.mask
+				continue
+			}
+
+			usage := fmt.Sprintf("%s: %s (%d-%d)", def.Name, s.Description, s.Min, s.Max)
+			settingFlags[def.Type][s.Key] = flag.Int(name, s.Default, usage)
+		}
+	}
+}
+
+// tablesFlag is a flag holding a list of times tables such as "6,7,8"
+type tablesFlag struct {
+	mask *int
+}
+
+// String returns the tables as a comma-separated list
+func (f tablesFlag) String() string {
+	if f.mask == nil {
+		return ""
+	}
+	return problems.FormatTables(problems.MaskTables(*f.mask))
+}
+
+// Set parses a list of tables
+func (f tablesFlag) Set(value string) error {
+	tables, err := problems.ParseTables(value)
+	if err != nil {
+		return err
+	}
+	*f.mask = problems.TablesMask(tables)
+	return nil
+}
+
+// loadConfigs reads generator settings from config.json in the data
+// directory, then applies any setting flags given on the command line
+func loadConfigs(dataDir string) (map[problems.ProblemType]problems.Config, error) {
+	configs, err := problems.LoadConfigFile(filepath.Join(dataDir, "config.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	// Only flags that were actually set override the config file
+	flag.Visit(func(f *flag.Flag) {
+		name, key, ok := strings.Cut(f.Name, ".")
+		value, known := settingFlags[problems.ProblemType(name)][key]
+		if !ok || !known {
+			return
+		}
+
+		problemType := problems.ProblemType(name)
+		if configs[problemType] == nil {
+			configs[problemType] = problems.Config{}
+		}
+		configs[problemType][key] = *value
+	})
+
+	for problemType, config := range configs {
+		def, _ := problems.Lookup(problemType)
+		if err := def.Validate(config); err != nil {
+			return nil, err
+		}
+	}
+
+	return configs, nil
+}
+
+// loadWordLibrary adds the templates in word_problems.json in the data
+// directory, if there is one, to the bundled word problems
+func loadWordLibrary(dataDir string) error {
+	path := filepath.Join(dataDir, "word_problems.json")
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
+	}
+
+	library, err := problems.LoadWordLibrary(path)
+	if err != nil {
+		return err
+	}
+	problems.AddWordLibrary(library)
+	return nil
+}
+
+// setup is a generator ready to play, with what is saved about its settings
+type setup struct {
+	generator  problems.Generator
+	difficulty int
+
+	// tier is the difficulty tier chosen, counting from 1, or 0 if the
+	// settings didn't come from a tier
+	tier int
+
+	// intro is shown with the instructions before the game starts
+	intro []string
+}
+
+// newGenerator creates a generator for a problem type, asking the player
+// for the times tables to practice and a difficulty tier, if the type has
+// them, and for any settings with choices that the config doesn't already fix
+func newGenerator(userInterface ui.UI, def problems.Definition, config problems.Config, unlocked int) (*setup, error) {
+	config = maps.Clone(config)
+	if config == nil {
+		config = problems.Config{}
+	}
+
+	// Chosen times tables take the place of a tier, since a tier's largest
+	// factor would rule out the bigger tables, so they are asked for first
+	for _, s := range def.Settings {
+		if _, set := config[s.Key]; s.Tables && !set {
+			tables, err := askTables(userInterface)
+			if err != nil {
+				return nil, err
+			}
+			config[s.Key] = problems.TablesMask(tables)
+		}
+	}
+
+	// Tiers only apply when the config leaves their settings open
+	tier := 0
+	if len(def.Tiers) > 0 && !fixesTier(def, config) && !choosesTables(def, config) {
+		var err error
+		if tier, err = askTier(userInterface, def, unlocked); err != nil {
+			return nil, err
+		}
+		maps.Copy(config, def.Tiers[tier-1].Config)
+	}
+
+	for _, s := range def.Settings {
+		if _, set := config[s.Key]; set || len(s.Choices) == 0 {
+			continue
+		}
+
+		userInterface.ShowMessage(fmt.Sprintf("\nChoose the %s:", s.Description))
+		choice, err := userInterface.ShowMenu(s.Choices)
+		if err != nil {
+			return nil, err
+		}
+		config[s.Key] = s.Min + choice
+	}
+
+	generator, err := def.Generator(config)
+	if err != nil {
+		return nil, err
+	}
+	return &setup{generator: generator, difficulty: def.Level(config), tier: tier}, nil
+}
+
+// fixesTier reports whether config sets any of the settings that tiers set
+func fixesTier(def problems.Definition, config problems.Config) bool {
+	for _, tier := range def.Tiers {
+		for key := range tier.Config {
+			if _, set := config[key]; set {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// choosesTables reports whether config limits the game to some times tables
+func choosesTables(def problems.Definition, config problems.Config) bool {
+	for _, s := range def.Settings {
+		if s.Tables && config[s.Key] != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// askTier asks which unlocked tier to play, counting from 1
+func askTier(userInterface ui.UI, def problems.Definition, unlocked int) (int, error) {
+	options := make([]string, len(def.Tiers))
+	for i, tier := range def.Tiers {
+		options[i] = tier.Name
+		if i >= unlocked {
+			options[i] += " (locked)"
+		}
+	}
+
+	for {
+		userInterface.ShowMessage("\nChoose a difficulty:")
+		choice, err := userInterface.ShowMenu(options)
+		if err != nil {
+			return 0, err
+		}
+		if choice < unlocked {
+			return choice + 1, nil
+		}
+		userInterface.ShowMessage(fmt.Sprintf("That difficulty is locked. Score %d%% or more %d times in a row on %s to unlock the next one.",
+			levels.MasteryPercent, levels.MasterySessions, def.Tiers[unlocked-1].Name))
+	}
+}
+
+// askTables asks which times tables to practice until the answer is valid
+func askTables(userInterface ui.UI) ([]int, error) {
+	for {
+		input, err := userInterface.Ask("\nWhich times tables? (e.g. 6,7,8 or 2-5, Enter for all)")
+		if err != nil {
+			return nil, err
+		}
+
+		tables, err := problems.ParseTables(input)
+		if err == nil {
+			return tables, nil
+		}
+		userInterface.ShowMessage(fmt.Sprintf("Error: %v", err))
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"math-game/internal/levels"
+	"math-game/internal/problems"
+	"math-game/internal/ui"
+)
+
+// scriptedUI answers questions and menus from a script, failing the test if
+// it runs out of answers
+type scriptedUI struct {
+	ui.UI
+	t       *testing.T
+	answers []string
+	choices []int
+}
+
+func (s *scriptedUI) Ask(prompt string) (string, error) {
+	if len(s.answers) == 0 {
+		s.t.Fatalf("Unexpected question: %s", prompt)
+	}
+	answer := s.answers[0]
+	s.answers = s.answers[1:]
+	return answer, nil
+}
+
+func (s *scriptedUI) ShowMenu(options []string) (int, error) {
+	if len(s.choices) == 0 {
+		s.t.Fatalf("Unexpected menu: %v", options)
+	}
+	choice := s.choices[0]
+	s.choices = s.choices[1:]
+	return choice, nil
+}
+
+func (s *scriptedUI) ShowMessage(message string) {}
+
+func TestNewGeneratorTablesOnFreshProfile(t *testing.T) {
+	tracker, err := levels.Load(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, problemType := range []problems.ProblemType{problems.Multiplication, problems.Division} {
+		def, _ := problems.Lookup(problemType)
+		if unlocked := tracker.Unlocked(def); unlocked != 1 {
+			t.Fatalf("Expected only the first tier unlocked on a fresh profile, got %d", unlocked)
+		}
+
+		// Choosing tables above the first tier's largest factor skips the tier
+		userInterface := &scriptedUI{t: t, answers: []string{"6,7,8"}}
+		s, err := newGenerator(userInterface, def, nil, tracker.Unlocked(def))
+		if err != nil {
+			t.Fatalf("%s: failed to create a generator for the 6s, 7s and 8s: %v", def.Name, err)
+		}
+		if s.tier != 0 {
+			t.Errorf("%s: expected no tier, got %d", def.Name, s.tier)
+		}
+		focus, ok := s.generator.(problems.TableFocus)
+		if !ok || !slices.Equal(focus.Tables(), []int{6, 7, 8}) {
+			t.Errorf("%s: expected the 6s, 7s and 8s to be practiced", def.Name)
+		}
+
+		// Practicing every table still asks for a tier
+		userInterface = &scriptedUI{t: t, answers: []string{""}, choices: []int{0}}
+		s, err = newGenerator(userInterface, def, nil, tracker.Unlocked(def))
+		if err != nil {
+			t.Fatalf("%s: failed to create a generator: %v", def.Name, err)
+		}
+		if s.tier != 1 {
+			t.Errorf("%s: expected tier 1, got %d", def.Name, s.tier)
+		}
+		if len(userInterface.choices) != 0 {
+			t.Errorf("%s: expected the tier to be asked for", def.Name)
+		}
+	}
+}
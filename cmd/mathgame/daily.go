@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"math-game/internal/game"
+	"math-game/internal/problems"
+	"math-game/internal/ui"
+)
+
+// dailyOption returns the main menu entry for the daily challenge, showing
+// whether it has been played today and the current streak
+func dailyOption(player *profile) string {
+	results, err := player.storage.GetResults(problems.Daily, 0)
+	if err != nil {
+		return "Play " + problems.DailyName
+	}
+
+	// A streak that ended yesterday still counts, since today's challenge
+	// can continue it
+	today := time.Now()
+	streak := player.achievements.DailyStreak(today)
+	if streak == 0 {
+		streak = player.achievements.DailyStreak(today.AddDate(0, 0, -1))
+	}
+	switch {
+	case playedOn(results, today) != nil:
+		return fmt.Sprintf("%s (done today, %s)", problems.DailyName, streakLabel(streak))
+	case streak > 0:
+		return fmt.Sprintf("Play %s (%s)", problems.DailyName, streakLabel(streak))
+	default:
+		return "Play " + problems.DailyName
+	}
+}
+
+// playDaily runs today's daily challenge, which every profile can play once
+// a day
+func playDaily(userInterface ui.UI, player *profile) {
+	results, err := player.storage.GetResults(problems.Daily, 0)
+	if err != nil {
+		userInterface.ShowMessage(fmt.Sprintf("Error retrieving history: %v", err))
+		return
+	}
+
+	today := time.Now()
+	if result := playedOn(results, today); result != nil {
+		userInterface.ShowMessage(fmt.Sprintf("\nYou've already played today's challenge and scored %d / %d. Come back tomorrow for a new one!",
+			result.CorrectCount, result.TotalCount))
+		userInterface.Ask("Press Enter to continue...")
+		return
+	}
+
+	// Show how yesterday went and what today would make the streak
+	var intro []string
+	if result := playedOn(results, today.AddDate(0, 0, -1)); result != nil {
+		intro = append(intro, fmt.Sprintf("Yesterday you scored %d / %d (%.1f%%). Can you beat it?",
+			result.CorrectCount, result.TotalCount, result.PercentCorrect()))
+	}
+	if streak := player.achievements.DailyStreak(today.AddDate(0, 0, -1)); streak > 0 {
+		intro = append(intro, fmt.Sprintf("Finish today's challenge for a %s!", streakLabel(streak+1)))
+	}
+
+	playGame(userInterface, player, &setup{
+		generator:  problems.NewDailyGenerator(today),
+		difficulty: problems.DailyDifficulty,
+		intro:      intro,
+	})
+}
+
+// playedOn returns the daily challenge result from the calendar day of date,
+// or nil if it wasn't played that day
+func playedOn(results []game.Result, date time.Time) *game.Result {
+	for i, result := range results {
+		if sameDay(result.CompletionTime, date) {
+			return &results[i]
+		}
+	}
+	return nil
+}
+
+// sameDay reports whether two times fall on the same local calendar day
+func sameDay(a, b time.Time) bool {
+	y1, m1, d1 := a.Local().Date()
+	y2, m2, d2 := b.Local().Date()
+	return y1 == y2 && m1 == m2 && d1 == d2
+}
+
+// streakLabel returns a streak length such as "3-day streak"
+func streakLabel(n int) string {
+	return fmt.Sprintf("%d-day streak", n)
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"time"
+
+	"math-game/internal/goals"
+	"math-game/internal/problems"
+	"math-game/internal/ui"
+)
+
+// goalsCommand lists, adds or removes the goals of a profile. Only a parent
+// can add or remove them.
+func goalsCommand(userInterface ui.UI, dataDir string, player *profile, args []string) error {
+	if len(args) == 0 {
+		args = []string{"list"}
+	}
+
+	switch args[0] {
+	case "list":
+		listGoals(player)
+		return nil
+	case "add":
+		goal, err := parseGoal(args[1:])
+		if err != nil {
+			return err
+		}
+		if err := requirePIN(userInterface, dataDir); err != nil {
+			return err
+		}
+		if err := player.goals.Add(goal); err != nil {
+			return err
+		}
+		fmt.Printf("Added goal: %s\n", goal)
+		return nil
+	case "remove":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: goals remove NUMBER")
+		}
+		n, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("goal number must be a number")
+		}
+		if err := requirePIN(userInterface, dataDir); err != nil {
+			return err
+		}
+		if err := player.goals.Remove(n - 1); err != nil {
+			return err
+		}
+		fmt.Printf("Removed goal %d\n", n)
+		return nil
+	default:
+		return fmt.Errorf("unknown goals command %q: use list, add or remove", args[0])
+	}
+}
+
+// parseGoal reads a goal from the flags of "goals add", such as
+// "-type multiplication -sessions 3 -percent 90 -per week"
+func parseGoal(args []string) (goals.Goal, error) {
+	flags := flag.NewFlagSet("goals add", flag.ContinueOnError)
+	problemType := flags.String("type", "", "game type the goal is for (any game if not set)")
+	sessions := flags.Int("sessions", 0, "number of sessions to play")
+	minutes := flags.Int("minutes", 0, "minutes of practice")
+	percent := flags.Int("percent", 0, "score a session needs to count")
+	per := flags.String("per", "week", "how often the goal starts over: day or week")
+	if err := flags.Parse(args); err != nil {
+		return goals.Goal{}, err
+	}
+
+	period, err := goals.ParsePeriod(*per)
+	if err != nil {
+		return goals.Goal{}, err
+	}
+	goal := goals.Goal{
+		ProblemType: problems.ProblemType(*problemType),
+		Sessions:    *sessions,
+		Minutes:     *minutes,
+		MinPercent:  *percent,
+		Per:         period,
+	}
+	return goal, goal.Validate()
+}
+
+// listGoals prints every goal of the profile, numbered for "goals remove"
+func listGoals(player *profile) {
+	list := player.goals.Goals()
+	if len(list) == 0 {
+		fmt.Println("No goals set. Add one with, for example:")
+		fmt.Println("  mathgame goals add -type multiplication -sessions 3 -percent 90 -per week")
+		return
+	}
+
+	now := time.Now()
+	for i, goal := range list {
+		fmt.Printf("%d. %s\n", i+1, goalStatus(goal, now))
+	}
+}
+
+// showGoals prints the progress of each goal above the main menu
+func showGoals(player *profile) {
+	list := player.goals.Goals()
+	if len(list) == 0 {
+		return
+	}
+
+	now := time.Now()
+	fmt.Println("Goals:")
+	for _, goal := range list {
+		fmt.Printf("  %s\n", goalStatus(goal, now))
+	}
+	fmt.Println()
+}
+
+// goalStatus describes a goal and its progress in the current period
+func goalStatus(goal goals.Goal, now time.Time) string {
+	progress, target, met := goal.Status(now)
+	mark := "[ ]"
+	if met {
+		mark = "[x]"
+	}
+	period := "this week"
+	if goal.Per == goals.Daily {
+		period = "today"
+	}
+	return fmt.Sprintf("%s %s (%d/%d %s)", mark, goal, min(progress, target), target, period)
+}
@@ -1,12 +1,15 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
 
 	"math-game/internal/game"
 	"math-game/internal/history"
+	"math-game/internal/levels"
 	"math-game/internal/problems"
 	"math-game/internal/ui"
 )
@@ -15,9 +18,74 @@ const (
 	totalProblems = 20
 )
 
+// seed, when set, makes every seedable generator produce the same problems
+// each time, so a whole class can practice the same set
+var seed = flag.Int64("seed", 0, "seed for repeatable problem sets (0 for random)")
+
+// ascii forces plain ASCII math symbols, e.g. 7^2 instead of 7²
+var ascii = flag.Bool("ascii", false, "show math symbols in plain ASCII (7^2 instead of 7²)")
+
+// profileName chooses whose history and badges are used. The default
+// profile keeps them in the data directory itself.
+var profileName = flag.String("profile", "", "name of the player's profile, e.g. maya")
+
+// unlock and lock let a parent open every tier of a problem type, whatever
+// the child has mastered, or take that back
+var (
+	unlock = flag.String("unlock", "", "unlock every difficulty tier of a game type for the profile, or \"all\"")
+	lock   = flag.String("lock", "", "undo -unlock for a game type, or \"all\"")
+)
+
+// retryPolicy says what happens after a wrong answer, set by -retry
+var retryPolicy game.RetryPolicy
+
+// Sampling flags control how problems are picked over a session
+var (
+	noRepeats       = flag.Bool("no-repeats", true, "avoid asking the same problem twice in a session")
+	sameTurnarounds = flag.Bool("same-turnarounds", true, "count turnarounds such as 3 × 4 and 4 × 3 as the same problem")
+	balanced        = flag.Bool("balanced", true, "spread problems evenly over every times table")
+)
+
 func main() {
+	// Create data directory
+	dataDir := getDataDir()
+
+	// Add the teacher's own problem types and quizzes, before the flags so
+	// their settings get flags too
+	if err := problems.RegisterCustomDir(filepath.Join(dataDir, "generators")); err != nil {
+		fmt.Printf("Error loading custom problems: %v\n", err)
+		os.Exit(1)
+	}
+	if err := problems.RegisterQuizDir(filepath.Join(dataDir, "quizzes")); err != nil {
+		fmt.Printf("Error loading quizzes: %v\n", err)
+		os.Exit(1)
+	}
+
+	flag.Var(&retryPolicy, "retry", "what happens after a wrong answer: none, once (try again straight away) or requeue (ask again at the end)")
+	registerSettingFlags()
+	flag.Parse()
+
+	// Load the player's history and badges
+	player, err := loadProfile(dataDir, *profileName)
+	if err != nil {
+		fmt.Printf("Error loading profile: %v\n", err)
+		os.Exit(1)
+	}
+
 	// Create UI
 	userInterface := ui.NewTerminalUI()
+	if *ascii {
+		userInterface.SetASCII(true)
+	}
+
+	// Run a command such as "goals add" instead of the game if one was given
+	if flag.NArg() > 0 {
+		if err := runCommand(userInterface, dataDir, player, flag.Args()); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
 	userInterface.Clear()
 
 	// Welcome message
@@ -26,19 +94,42 @@ func main() {
 	fmt.Println("Practice your math skills with fun challenges!")
 	fmt.Println()
 
-	// Create data directory
-	dataDir := getDataDir()
+	if player.name != "" {
+		fmt.Printf("Playing as %s\n\n", player.name)
+	}
+
+	// Changing settings or tier locks is for parents only
+	if protectedFlagsSet() {
+		if err := requirePIN(userInterface, dataDir); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
 
-	// Create history storage
-	storage, err := history.NewFileStorage(dataDir)
+	// Apply a parent's overrides of the tier locks
+	if err := overrideTiers(player, *unlock, true); err != nil {
+		fmt.Printf("Error unlocking tiers: %v\n", err)
+		os.Exit(1)
+	}
+	if err := overrideTiers(player, *lock, false); err != nil {
+		fmt.Printf("Error locking tiers: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Load generator settings and extra word problems
+	configs, err := loadConfigs(dataDir)
 	if err != nil {
-		fmt.Printf("Error initializing storage: %v\n", err)
+		fmt.Printf("Error loading settings: %v\n", err)
+		os.Exit(1)
+	}
+	if err := loadWordLibrary(dataDir); err != nil {
+		fmt.Printf("Error loading word problems: %v\n", err)
 		os.Exit(1)
 	}
 
 	// Main game loop
 	for {
-		mainMenu(userInterface, storage)
+		mainMenu(userInterface, player, configs)
 	}
 }
 
@@ -51,27 +142,35 @@ func getDataDir() string {
 	}
 
 	dataDir := filepath.Join(homeDir, ".mathgame")
-	if err := os.MkdirAll(dataDir, 0755); err != nil {
+	if err := os.MkdirAll(dataDir, 0700); err != nil {
 		fmt.Printf("Error creating data directory: %v\n", err)
 		os.Exit(1)
 	}
 
+	// Keep history and the PIN private, including in data directories made
+	// before they were
+	if err := os.Chmod(dataDir, 0700); err != nil {
+		fmt.Printf("Error securing data directory: %v\n", err)
+		os.Exit(1)
+	}
+
 	return dataDir
 }
 
 // mainMenu displays the main menu and handles user selection
-func mainMenu(userInterface ui.UI, storage history.Storage) {
-	options := []string{
-		"Play Addition",
-		"Play Subtraction",
-		"Play Multiplication",
-		"Play Division",
-		"View Addition History",
-		"View Subtraction History",
-		"View Multiplication History",
-		"View Division History",
-		"Exit",
+func mainMenu(userInterface ui.UI, player *profile, configs map[problems.ProblemType]problems.Config) {
+	definitions := problems.Definitions()
+
+	options := make([]string, 0, len(definitions)+4)
+	for _, def := range definitions {
+		options = append(options, "Play "+def.Name)
 	}
+	options = append(options, dailyOption(player), "View History", "Trophy Case", "Exit")
+
+	level := player.levels.Level()
+	fmt.Printf("Level %d - %d XP (%d XP to level %d)\n\n",
+		level, player.levels.XP(), levels.XPForLevel(level+1)-player.levels.XP(), level+1)
+	showGoals(player)
 
 	choice, err := userInterface.ShowMenu(options)
 	if err != nil {
@@ -79,77 +178,290 @@ func mainMenu(userInterface ui.UI, storage history.Storage) {
 		return
 	}
 
-	switch choice {
-	case 0: // Addition
-		playGame(userInterface, storage, problems.NewAdditionGenerator(2))
-	case 1: // Subtraction
-		playGame(userInterface, storage, problems.NewSubtractionGenerator(2))
-	case 2: // Multiplication
-		playGame(userInterface, storage, problems.NewMultiplicationGenerator(12))
-	case 3: // Division
-		playGame(userInterface, storage, problems.NewDivisionGenerator(12))
-	case 4: // View Addition History
-		showHistory(userInterface, storage, problems.Addition)
-	case 5: // View Subtraction History
-		showHistory(userInterface, storage, problems.Subtraction)
-	case 6: // View Multiplication History
-		showHistory(userInterface, storage, problems.Multiplication)
-	case 7: // View Division History
-		showHistory(userInterface, storage, problems.Division)
-	case 8: // Exit
+	switch {
+	case choice < len(definitions): // Play
+		def := definitions[choice]
+		s, err := newGenerator(userInterface, def, configs[def.Type], player.levels.Unlocked(def))
+		if err != nil {
+			userInterface.ShowMessage(fmt.Sprintf("Error: %v", err))
+			return
+		}
+		playGame(userInterface, player, s)
+	case choice == len(definitions): // Daily Challenge
+		playDaily(userInterface, player)
+	case choice == len(definitions)+1: // View History
+		historyMenu(userInterface, player.storage)
+	case choice == len(definitions)+2: // Trophy Case
+		userInterface.ShowTrophyCase(player.achievements.Badges())
+	default: // Exit
 		fmt.Println("Thank you for playing Math Game!")
 		os.Exit(0)
 	}
 }
 
-// playGame runs a game session with the given problem generator
-func playGame(userInterface ui.UI, storage history.Storage, generator problems.Generator) {
+// historyMenu asks which problem type to show history for
+func historyMenu(userInterface ui.UI, storage history.Storage) {
+	definitions := problems.Definitions()
+
+	options := make([]string, 0, len(definitions)+1)
+	for _, def := range definitions {
+		options = append(options, def.Name)
+	}
+	options = append(options, problems.DailyName)
+
 	userInterface.Clear()
+	fmt.Println("Which history would you like to see?")
+	choice, err := userInterface.ShowMenu(options)
+	if err != nil {
+		userInterface.ShowMessage(fmt.Sprintf("Error: %v", err))
+		return
+	}
+
+	problemType := problems.Daily
+	if choice < len(definitions) {
+		problemType = definitions[choice].Type
+	}
+	showHistory(userInterface, storage, problemType)
+}
+
+// playGame runs a game session with the given generator and settings
+func playGame(userInterface ui.UI, player *profile, setup *setup) {
+	userInterface.Clear()
+	generator := setup.generator
+
+	// Remember which times tables were chosen, to save with the result
+	var tables []int
+	focus, tableGame := generator.(problems.TableFocus)
+	if tableGame {
+		tables = focus.Tables()
+	}
+
+	// Quizzes ask each of their problems once; other problems are sampled
+	// so the session avoids repeats and covers every table
+	count := totalProblems
+	if fixed, ok := generator.(problems.FixedLength); ok {
+		count = fixed.Len()
+	} else {
+		generator = problems.NewSampler(generator, problems.SamplingPolicy{
+			NoRepeats:       *noRepeats,
+			SameTurnarounds: *sameTurnarounds,
+			Balanced:        *balanced,
+		})
+	}
 
 	// Show game start message
 	fmt.Printf("Starting %s Game\n", generator.Name())
-	fmt.Printf("You will be given %d problems to solve.\n", totalProblems)
+	fmt.Printf("You will be given %d problems to solve.\n", count)
+	fmt.Println("Answer quickly and keep a streak going for bonus points.")
+	fmt.Println("Type ? instead of an answer if you'd like a hint, for half points.")
+	for _, line := range setup.intro {
+		fmt.Println(line)
+	}
 	fmt.Println("Press Enter to start...")
 	fmt.Scanln()
 
+	// Use the same problems every time if a seed was given
+	if seeder, ok := generator.(problems.Seeder); ok && *seed != 0 {
+		seeder.Seed(*seed)
+	}
+
 	// Create and start a new game session
-	session := game.NewSession(generator, totalProblems)
+	session := game.NewSession(generator, count)
+	session.Difficulty = setup.difficulty
 	session.Start()
 
 	// Present each problem
-	for i := 0; i < totalProblems; i++ {
+	var missed, requeued []problems.Problem
+	for i := 0; i < count; i++ {
 		problem := generator.Generate()
-		userAnswer, err := userInterface.DisplayProblem(problem, i+1, totalProblems)
 
-		if err != nil {
-			userInterface.ShowMessage(fmt.Sprintf("Error: %v", err))
-			i-- // Retry the same problem
-			continue
+		// In step-by-step mode, check the working before the final answer
+		if len(problem.Steps) > 0 {
+			askSteps(userInterface, problem)
 		}
 
+		session.StartProblem()
+		userAnswer, hinted := askProblem(userInterface, problem, i+1, count)
+
 		// Check answer and record result
-		correct := userAnswer == problem.Answer
-		session.AddResult(correct)
+		correct := problem.IsCorrect(userAnswer)
+		session.AddResult(problem, correct)
 
-		// Show feedback
 		if correct {
 			userInterface.ShowMessage("Correct!")
 		} else {
-			userInterface.ShowMessage(fmt.Sprintf("Incorrect. The correct answer is %d.", problem.Answer))
+			missed = append(missed, problem)
+
+			// Apply the retry policy to the wrong answer
+			switch retryPolicy {
+			case game.RetryOnce:
+				userInterface.ShowMessage("Not quite. Have another try.")
+				retryAnswer, retryHinted := askProblem(userInterface, problem, i+1, count)
+				hinted = hinted || retryHinted
+				correct = problem.IsCorrect(retryAnswer)
+				session.AddRetry(correct)
+				showFeedback(userInterface, problem, correct)
+			case game.RequeueMissed:
+				userInterface.ShowMessage("Not quite. We'll come back to this one at the end.")
+				requeued = append(requeued, problem)
+			default:
+				showFeedback(userInterface, problem, false)
+			}
+		}
+
+		if hinted {
+			session.AddHint()
+		}
+	}
+
+	// Ask requeued problems once more before the session ends
+	if len(requeued) > 0 {
+		userInterface.ShowMessage("\nLet's try the ones you missed again.")
+		for i, problem := range requeued {
+			userAnswer, _ := askProblem(userInterface, problem, i+1, len(requeued))
+			correct := problem.IsCorrect(userAnswer)
+			session.AddRetry(correct)
+			showFeedback(userInterface, problem, correct)
 		}
 	}
 
 	// End the session and get results
 	session.End()
 	result := session.GetResult()
+	result.Tables = tables
+	if tableGame {
+		result.TableScores = game.TableScores(session.Answers, tables)
+	}
+	result.Tier = setup.tier
 
-	// Save result to history
-	if err := storage.SaveResult(result); err != nil {
+	// Check for new badges, levels and goals met, then save result to history
+	badges, err := player.achievements.Record(result, session.Answers)
+	if err != nil {
+		userInterface.ShowMessage(fmt.Sprintf("Failed to save achievements: %v", err))
+	}
+	for _, badge := range badges {
+		result.Badges = append(result.Badges, badge.Name)
+	}
+	update, err := player.levels.Record(result, player.storage)
+	if err != nil {
+		userInterface.ShowMessage(fmt.Sprintf("Failed to save levels: %v", err))
+	}
+	result.XP, result.Level, result.LevelUp = update.XP, update.Level, update.LevelUp
+	for _, tier := range update.Unlocked {
+		result.Unlocks = append(result.Unlocks, tier.Name)
+	}
+	met, err := player.goals.Record(result)
+	if err != nil {
+		userInterface.ShowMessage(fmt.Sprintf("Failed to save goals: %v", err))
+	}
+	for _, goal := range met {
+		result.GoalsMet = append(result.GoalsMet, goal.String())
+	}
+	if err := player.storage.SaveResult(result); err != nil {
 		userInterface.ShowMessage(fmt.Sprintf("Failed to save result: %v", err))
 	}
 
 	// Show results
 	userInterface.ShowResults(result)
+
+	if len(missed) > 0 {
+		reviewMistakes(userInterface, missed)
+	}
+}
+
+// showFeedback says whether an answer was correct, working through the
+// problem after a wrong answer
+func showFeedback(userInterface ui.UI, problem problems.Problem, correct bool) {
+	if correct {
+		userInterface.ShowMessage("Correct!")
+		return
+	}
+
+	userInterface.ShowMessage(fmt.Sprintf("Incorrect. The correct answer is %s.", problem.FormatAnswer()))
+	if explanation, ok := problems.Explain(problem); ok {
+		for _, step := range explanation.Steps {
+			userInterface.ShowMessage("  " + step)
+		}
+	}
+}
+
+// reviewMistakes offers to replay the problems missed in a session. The
+// review is practice only and isn't saved to history.
+func reviewMistakes(userInterface ui.UI, missed []problems.Problem) {
+	userInterface.ShowMessage(fmt.Sprintf("\nYou missed %d %s this time.", len(missed), plural(len(missed), "problem")))
+	choice, err := userInterface.ShowMenu([]string{"Review your mistakes", "Back to the main menu"})
+	if err != nil || choice != 0 {
+		return
+	}
+
+	userInterface.Clear()
+	right := 0
+	for i, problem := range missed {
+		userAnswer, _ := askProblem(userInterface, problem, i+1, len(missed))
+		correct := problem.IsCorrect(userAnswer)
+		if correct {
+			right++
+		}
+		showFeedback(userInterface, problem, correct)
+	}
+
+	userInterface.ShowMessage(fmt.Sprintf("\nYou got %d of %d right this time.", right, len(missed)))
+}
+
+// plural returns noun, with an "s" added unless n is 1
+func plural(n int, noun string) string {
+	if n == 1 {
+		return noun
+	}
+	return noun + "s"
+}
+
+// askProblem asks a problem until it gets an answer, giving a hint
+// whenever the player asks for one. It reports whether a hint was given.
+func askProblem(userInterface ui.UI, problem problems.Problem, problemNum, total int) (int, bool) {
+	hinted := false
+	for {
+		answer, err := userInterface.DisplayProblem(problem, problemNum, total)
+		if err == nil {
+			return answer, hinted
+		}
+
+		if !errors.Is(err, ui.ErrHintRequested) {
+			userInterface.ShowMessage(fmt.Sprintf("Error: %v", err))
+			continue
+		}
+
+		explanation, ok := problems.Explain(problem)
+		if !ok || explanation.Hint == "" {
+			userInterface.ShowMessage("Sorry, there's no hint for this one.")
+			continue
+		}
+		userInterface.ShowMessage("Hint: " + explanation.Hint)
+		hinted = true
+	}
+}
+
+// askSteps asks for each intermediate step of a problem, explaining any
+// mistakes along the way
+func askSteps(userInterface ui.UI, problem problems.Problem) {
+	userInterface.ShowMessage(fmt.Sprintf("\nLet's work out %s step by step.", problem.Question))
+
+	for i := 0; i < len(problem.Steps); i++ {
+		step := problem.Steps[i]
+		answer, err := userInterface.DisplayStep(step.Problem(problem), i+1, len(problem.Steps))
+
+		if err != nil {
+			userInterface.ShowMessage(fmt.Sprintf("Error: %v", err))
+			i-- // Retry the same step
+			continue
+		}
+
+		if answer == step.Answer {
+			userInterface.ShowMessage("  Correct!")
+		} else {
+			userInterface.ShowMessage("  " + step.Explain(answer))
+		}
+	}
 }
 
 // showHistory displays the history for a specific problem type
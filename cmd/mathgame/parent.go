@@ -0,0 +1,174 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"math-game/internal/pin"
+	"math-game/internal/problems"
+	"math-game/internal/ui"
+)
+
+// runCommand runs a command given after the flags, such as
+// "mathgame -profile maya goals list"
+func runCommand(userInterface ui.UI, dataDir string, player *profile, args []string) error {
+	switch args[0] {
+	case "goals":
+		return goalsCommand(userInterface, dataDir, player, args[1:])
+	case "pin":
+		return changePIN(userInterface, dataDir)
+	case "reset-history":
+		return resetHistory(userInterface, dataDir, player)
+	case "delete-profile":
+		return deleteProfile(userInterface, dataDir, player)
+	default:
+		return fmt.Errorf("unknown command %q: use goals, pin, reset-history or delete-profile", args[0])
+	}
+}
+
+// maxPINAttempts is how many wrong PINs are allowed before giving up
+const maxPINAttempts = 3
+
+// requirePIN asks for the parent's PIN before a protected change made
+// through the game. Until a PIN is set with "mathgame pin", protected changes
+// are refused; whoever runs that first chooses the PIN.
+func requirePIN(userInterface ui.UI, dataDir string) error {
+	parentPIN, err := pin.Load(dataDir)
+	if err != nil {
+		return err
+	}
+	if !parentPIN.IsSet() {
+		return errors.New("this needs a parent PIN, and none has been set yet; ask a parent to run \"mathgame pin\"")
+	}
+
+	for attempt := 0; attempt < maxPINAttempts; attempt++ {
+		code, err := userInterface.Ask("Parent PIN:")
+		if err != nil {
+			return err
+		}
+		if parentPIN.Check(code) {
+			return nil
+		}
+		userInterface.ShowMessage("That PIN isn't right.")
+	}
+	return errors.New("wrong PIN")
+}
+
+// choosePIN asks for a new PIN twice and saves it
+func choosePIN(userInterface ui.UI, parentPIN *pin.PIN) error {
+	code, err := userInterface.Ask("Choose a PIN of 4 to 8 digits:")
+	if err != nil {
+		return err
+	}
+	if err := pin.Valid(code); err != nil {
+		return err
+	}
+
+	again, err := userInterface.Ask("Type the PIN again:")
+	if err != nil {
+		return err
+	}
+	if again != code {
+		return errors.New("the PINs didn't match")
+	}
+
+	if err := parentPIN.Set(code); err != nil {
+		return err
+	}
+	userInterface.ShowMessage("Parent PIN saved.")
+	return nil
+}
+
+// protectedFlagsSet reports whether any flag that changes settings or tier
+// locks was given
+func protectedFlagsSet() bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		name, key, _ := strings.Cut(f.Name, ".")
+		if _, ok := settingFlags[problems.ProblemType(name)][key]; ok || f.Name == "unlock" || f.Name == "lock" {
+			set = true
+		}
+	})
+	return set
+}
+
+// changePIN sets the parent's PIN, asking for the current one first if
+// there is one. Anyone can set the first PIN, so a parent should set it
+// before a child plays.
+func changePIN(userInterface ui.UI, dataDir string) error {
+	parentPIN, err := pin.Load(dataDir)
+	if err != nil {
+		return err
+	}
+	if parentPIN.IsSet() {
+		if err := requirePIN(userInterface, dataDir); err != nil {
+			return err
+		}
+	}
+	return choosePIN(userInterface, parentPIN)
+}
+
+// resetHistory deletes the saved results of every game for the profile,
+// and the progress and daily streak built up from them
+func resetHistory(userInterface ui.UI, dataDir string, player *profile) error {
+	if err := requirePIN(userInterface, dataDir); err != nil {
+		return err
+	}
+	if !confirm(userInterface, fmt.Sprintf("Delete all history for %s?", profileLabel(player))) {
+		userInterface.ShowMessage("Nothing was deleted.")
+		return nil
+	}
+
+	types := []problems.ProblemType{problems.Daily}
+	for _, def := range problems.Definitions() {
+		types = append(types, def.Type)
+	}
+	for _, problemType := range types {
+		if err := player.storage.Clear(problemType); err != nil {
+			return err
+		}
+	}
+	if err := player.achievements.ResetProgress(); err != nil {
+		return err
+	}
+	userInterface.ShowMessage("History deleted.")
+	return nil
+}
+
+// deleteProfile deletes a named profile with its history, badges, levels
+// and goals
+func deleteProfile(userInterface ui.UI, dataDir string, player *profile) error {
+	if player.name == "" {
+		return errors.New("the default profile can't be deleted; use reset-history to clear its history")
+	}
+	if err := requirePIN(userInterface, dataDir); err != nil {
+		return err
+	}
+	if !confirm(userInterface, fmt.Sprintf("Delete %s and everything saved for it?", profileLabel(player))) {
+		userInterface.ShowMessage("Nothing was deleted.")
+		return nil
+	}
+
+	if err := os.RemoveAll(player.dir); err != nil {
+		return fmt.Errorf("failed to delete profile: %w", err)
+	}
+	userInterface.ShowMessage(fmt.Sprintf("Deleted %s.", profileLabel(player)))
+	return nil
+}
+
+// confirm asks a yes or no question, defaulting to no
+func confirm(userInterface ui.UI, question string) bool {
+	answer, err := userInterface.Ask(question + " Type yes to confirm:")
+	return err == nil && strings.EqualFold(answer, "yes")
+}
+
+// profileLabel names a profile in messages
+func profileLabel(player *profile) string {
+	if player.name == "" {
+		return "the default profile"
+	}
+	return "profile " + player.name
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"math-game/internal/achievements"
+	"math-game/internal/goals"
+	"math-game/internal/history"
+	"math-game/internal/levels"
+	"math-game/internal/problems"
+)
+
+// profile holds the history, badges, levels and goals of one player
+type profile struct {
+	name         string
+	dir          string
+	storage      history.Storage
+	achievements *achievements.Tracker
+	levels       *levels.Tracker
+	goals        *goals.Tracker
+}
+
+// loadProfile opens the profile with the given name. The default profile,
+// with an empty name, lives in the data directory itself so existing history
+// keeps working; named profiles live in profiles/<name>.
+func loadProfile(dataDir, name string) (*profile, error) {
+	dir := dataDir
+	if name != "" {
+		if !validProfileName(name) {
+			return nil, fmt.Errorf("invalid profile name %q: use letters, digits, dashes and underscores", name)
+		}
+		dir = filepath.Join(dataDir, "profiles", name)
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return nil, fmt.Errorf("failed to create profile directory: %w", err)
+		}
+	}
+
+	storage, err := history.NewFileStorage(dir)
+	if err != nil {
+		return nil, err
+	}
+	tracker, err := achievements.Load(dir)
+	if err != nil {
+		return nil, err
+	}
+	progress, err := levels.Load(dir)
+	if err != nil {
+		return nil, err
+	}
+	targets, err := goals.Load(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return &profile{name: name, dir: dir, storage: storage, achievements: tracker, levels: progress, goals: targets}, nil
+}
+
+// validProfileName reports whether name is safe to use as a directory name
+func validProfileName(name string) bool {
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+		default:
+			return false
+		}
+	}
+	return name != ""
+}
+
+// overrideTiers unlocks or relocks the tiers of the named problem type, or
+// of every type with tiers if the name is "all". An empty name does nothing.
+func overrideTiers(player *profile, name string, unlocked bool) error {
+	if name == "" {
+		return nil
+	}
+
+	var defs []problems.Definition
+	if name == "all" {
+		defs = problems.Definitions()
+	} else {
+		def, ok := problems.Lookup(problems.ProblemType(name))
+		if !ok {
+			return fmt.Errorf("unknown game type %q", name)
+		}
+		if len(def.Tiers) == 0 {
+			return fmt.Errorf("%s has no difficulty tiers", def.Name)
+		}
+		defs = append(defs, def)
+	}
+
+	for _, def := range defs {
+		if len(def.Tiers) == 0 {
+			continue
+		}
+		if err := player.levels.Override(def.Type, unlocked); err != nil {
+			return err
+		}
+		if unlocked {
+			fmt.Printf("Unlocked every difficulty of %s\n", def.Name)
+		} else {
+			fmt.Printf("Locked %s back to the difficulties earned\n", def.Name)
+		}
+	}
+	return nil
+}
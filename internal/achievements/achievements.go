@@ -0,0 +1,238 @@
+package achievements
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"math-game/internal/game"
+	"math-game/internal/problems"
+)
+
+// dateFormat is how practice days are recorded
+const dateFormat = "2006-01-02"
+
+// Badge is an achievement that can be unlocked
+type Badge struct {
+	ID          string
+	Name        string
+	Description string
+
+	// Unlocked is when the badge was earned, or zero if it hasn't been yet
+	Unlocked time.Time
+}
+
+// Progress is what is remembered about a profile between sessions, for
+// rules that look beyond a single session
+type Progress struct {
+	Sessions     int
+	PracticeDays []string // dates practiced, oldest first
+	TypesPlayed  []problems.ProblemType
+
+	// MasteredFacts lists the multiplication facts, such as "7×8", answered
+	// correctly at the first try without a hint
+	MasteredFacts []string
+
+	// DailyDays lists the dates the daily challenge was played, oldest first.
+	// They are kept here because history only keeps the last 10 results.
+	DailyDays []string
+}
+
+// Session is what rules are checked against: the result of the session
+// just played, its answers, and the profile's progress including it
+type Session struct {
+	Result   game.Result
+	Answers  []game.Answer
+	Progress Progress
+}
+
+// Rule unlocks a badge when a session meets its condition
+type Rule struct {
+	ID          string
+	Name        string
+	Description string
+	Earned      func(session Session) bool
+}
+
+// rules are the badges that can be earned, in the order they are listed
+var rules = []Rule{
+	{
+		ID: "first-session", Name: "First Steps", Description: "Finish your first session",
+		Earned: func(s Session) bool { return s.Progress.Sessions >= 1 },
+	},
+	{
+		ID: "perfect-score", Name: "Perfect Score", Description: "Get every problem right in a session",
+		Earned: func(s Session) bool { return s.Result.TotalCount >= 5 && s.Result.CorrectCount == s.Result.TotalCount },
+	},
+	{
+		ID: "hot-streak", Name: "Hot Streak", Description: "Get 10 problems right in a row",
+		Earned: func(s Session) bool { return s.Result.BestStreak >= 10 },
+	},
+	{
+		ID: "lightning", Name: "Lightning Fast", Description: "Finish a session in under a minute with at least 80% right",
+		Earned: func(s Session) bool {
+			return s.Result.TotalCount >= 10 && s.Result.Duration < time.Minute && s.Result.PercentCorrect() >= 80
+		},
+	},
+	{
+		ID: "week-streak", Name: "On a Roll", Description: "Practice 7 days in a row",
+		Earned: func(s Session) bool { return Streak(s.Progress.PracticeDays) >= 7 },
+	},
+	{
+		ID: "explorer", Name: "Explorer", Description: "Play 5 different kinds of problems",
+		Earned: func(s Session) bool { return len(s.Progress.TypesPlayed) >= 5 },
+	},
+	{
+		ID: "times-table-master", Name: "Times Table Master", Description: "Master 100 multiplication facts",
+		Earned: func(s Session) bool { return len(s.Progress.MasteredFacts) >= 100 },
+	},
+}
+
+// Streak returns how many days in a row end with the last practice day
+func Streak(days []string) int {
+	if len(days) == 0 {
+		return 0
+	}
+
+	streak := 1
+	for i := len(days) - 1; i > 0; i-- {
+		day, err1 := time.Parse(dateFormat, days[i])
+		previous, err2 := time.Parse(dateFormat, days[i-1])
+		if err1 != nil || err2 != nil || !previous.AddDate(0, 0, 1).Equal(day) {
+			break
+		}
+		streak++
+	}
+	return streak
+}
+
+// state is the contents of a profile's achievements file
+type state struct {
+	Unlocked map[string]time.Time
+	Progress Progress
+}
+
+// Tracker keeps the badges and progress of one profile in a file
+type Tracker struct {
+	path  string
+	state state
+}
+
+// Load reads the achievements kept in dir. A missing file means nothing has
+// been unlocked yet.
+func Load(dir string) (*Tracker, error) {
+	t := &Tracker{
+		path:  filepath.Join(dir, "achievements.json"),
+		state: state{Unlocked: map[string]time.Time{}},
+	}
+
+	data, err := os.ReadFile(t.path)
+	if os.IsNotExist(err) {
+		return t, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read achievements: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &t.state); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal achievements: %w", err)
+	}
+	if t.state.Unlocked == nil {
+		t.state.Unlocked = map[string]time.Time{}
+	}
+	return t, nil
+}
+
+// Record adds a finished session to the profile's progress, saves it, and
+// returns any badges the session unlocked
+func (t *Tracker) Record(result game.Result, answers []game.Answer) ([]Badge, error) {
+	finished := result.CompletionTime
+	if finished.IsZero() {
+		finished = time.Now()
+	}
+
+	progress := &t.state.Progress
+	progress.Sessions++
+	if day := finished.Format(dateFormat); !slices.Contains(progress.PracticeDays, day) {
+		progress.PracticeDays = append(progress.PracticeDays, day)
+		slices.Sort(progress.PracticeDays)
+	}
+	if day := finished.Format(dateFormat); result.ProblemType == problems.Daily && !slices.Contains(progress.DailyDays, day) {
+		progress.DailyDays = append(progress.DailyDays, day)
+		slices.Sort(progress.DailyDays)
+	}
+	if !slices.Contains(progress.TypesPlayed, result.ProblemType) {
+		progress.TypesPlayed = append(progress.TypesPlayed, result.ProblemType)
+	}
+	for _, answer := range answers {
+		problem := answer.Problem
+		if problem.Type != problems.Multiplication || !answer.Correct || answer.Hinted || len(problem.Operands) != 2 {
+			continue
+		}
+		fact := fmt.Sprintf("%d×%d", problem.Operands[0], problem.Operands[1])
+		if !slices.Contains(progress.MasteredFacts, fact) {
+			progress.MasteredFacts = append(progress.MasteredFacts, fact)
+		}
+	}
+
+	session := Session{Result: result, Answers: answers, Progress: *progress}
+	var unlocked []Badge
+	for _, rule := range rules {
+		if _, ok := t.state.Unlocked[rule.ID]; ok || !rule.Earned(session) {
+			continue
+		}
+		t.state.Unlocked[rule.ID] = finished
+		unlocked = append(unlocked, rule.badge(finished))
+	}
+
+	return unlocked, t.save()
+}
+
+// Badges returns every badge in the trophy case, unlocked or not
+func (t *Tracker) Badges() []Badge {
+	badges := make([]Badge, len(rules))
+	for i, rule := range rules {
+		badges[i] = rule.badge(t.state.Unlocked[rule.ID])
+	}
+	return badges
+}
+
+// ResetProgress forgets the progress built up from past sessions, such as
+// practice days and the daily challenge streak, and saves the change.
+// Badges already unlocked are kept.
+func (t *Tracker) ResetProgress() error {
+	t.state.Progress = Progress{}
+	return t.save()
+}
+
+// DailyStreak returns how many days in a row up to and including the day of
+// date the daily challenge was played
+func (t *Tracker) DailyStreak(date time.Time) int {
+	streak := 0
+	for slices.Contains(t.state.Progress.DailyDays, date.Format(dateFormat)) {
+		streak++
+		date = date.AddDate(0, 0, -1)
+	}
+	return streak
+}
+
+// save writes the achievements file
+func (t *Tracker) save() error {
+	data, err := json.MarshalIndent(t.state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal achievements: %w", err)
+	}
+
+	if err := os.WriteFile(t.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write achievements: %w", err)
+	}
+	return nil
+}
+
+// badge returns the badge for a rule, unlocked at the given time
+func (r Rule) badge(unlocked time.Time) Badge {
+	return Badge{ID: r.ID, Name: r.Name, Description: r.Description, Unlocked: unlocked}
+}
@@ -0,0 +1,204 @@
+package achievements
+
+import (
+	"testing"
+	"time"
+
+	"math-game/internal/game"
+	"math-game/internal/problems"
+)
+
+func TestRecordUnlocksBadges(t *testing.T) {
+	dir := t.TempDir()
+	tracker, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Failed to load: %v", err)
+	}
+
+	day := time.Date(2024, 3, 1, 16, 0, 0, 0, time.Local)
+	perfect := game.Result{ProblemType: problems.Addition, CorrectCount: 10, TotalCount: 10, Duration: 2 * time.Minute, CompletionTime: day}
+
+	badges, err := tracker.Record(perfect, nil)
+	if err != nil {
+		t.Fatalf("Failed to record: %v", err)
+	}
+	if len(badges) != 2 || badges[0].ID != "first-session" || badges[1].ID != "perfect-score" {
+		t.Errorf("Expected First Steps and Perfect Score, got %v", badges)
+	}
+
+	// Badges are only unlocked once
+	badges, _ = tracker.Record(perfect, nil)
+	if len(badges) != 0 {
+		t.Errorf("Expected no new badges, got %v", badges)
+	}
+
+	// Unlocked badges are kept in the profile
+	tracker, err = Load(dir)
+	if err != nil {
+		t.Fatalf("Failed to reload: %v", err)
+	}
+	unlocked := 0
+	for _, badge := range tracker.Badges() {
+		if !badge.Unlocked.IsZero() {
+			unlocked++
+		}
+	}
+	if unlocked != 2 {
+		t.Errorf("Expected 2 unlocked badges after reloading, got %d", unlocked)
+	}
+}
+
+func TestWeekStreak(t *testing.T) {
+	tracker, err := Load(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Date(2024, 3, 1, 16, 0, 0, 0, time.Local)
+	for day := 0; day < 7; day++ {
+		result := game.Result{ProblemType: problems.Addition, CorrectCount: 1, TotalCount: 10, CompletionTime: start.AddDate(0, 0, day)}
+		badges, err := tracker.Record(result, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		earned := false
+		for _, badge := range badges {
+			earned = earned || badge.ID == "week-streak"
+		}
+		if earned != (day == 6) {
+			t.Errorf("Day %d: expected week streak %v, got %v", day+1, day == 6, earned)
+		}
+	}
+}
+
+func TestDailyStreak(t *testing.T) {
+	dir := t.TempDir()
+	tracker, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Play the daily challenge for longer than history keeps results, with
+	// another game on a day after it
+	start := time.Date(2024, 3, 1, 16, 0, 0, 0, time.Local)
+	const days = 14
+	for day := 0; day < days; day++ {
+		result := game.Result{ProblemType: problems.Daily, CorrectCount: 15, TotalCount: 20, CompletionTime: start.AddDate(0, 0, day)}
+		if _, err := tracker.Record(result, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	last := start.AddDate(0, 0, days-1)
+	other := game.Result{ProblemType: problems.Addition, CorrectCount: 1, TotalCount: 10, CompletionTime: last.AddDate(0, 0, 1)}
+	if _, err := tracker.Record(other, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// The streak is kept in the profile
+	tracker, err = Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		date     time.Time
+		expected int
+	}{
+		{last, days},
+		{last.AddDate(0, 0, -1), days - 1},
+		{last.AddDate(0, 0, 1), 0},
+		{start.AddDate(0, 0, -1), 0},
+	}
+	for _, test := range tests {
+		if got := tracker.DailyStreak(test.date); got != test.expected {
+			t.Errorf("DailyStreak(%s) = %d, expected %d", test.date.Format("2006-01-02"), got, test.expected)
+		}
+	}
+}
+
+func TestResetProgress(t *testing.T) {
+	dir := t.TempDir()
+	tracker, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	day := time.Date(2024, 3, 1, 16, 0, 0, 0, time.Local)
+	for i := 0; i < 3; i++ {
+		result := game.Result{ProblemType: problems.Daily, CorrectCount: 20, TotalCount: 20, CompletionTime: day.AddDate(0, 0, i)}
+		if _, err := tracker.Record(result, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tracker.ResetProgress(); err != nil {
+		t.Fatalf("Failed to reset progress: %v", err)
+	}
+
+	// The streak starts over, but badges already earned are kept
+	tracker, err = Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if streak := tracker.DailyStreak(day.AddDate(0, 0, 2)); streak != 0 {
+		t.Errorf("Expected the daily streak to be reset, got %d", streak)
+	}
+	if tracker.Badges()[0].Unlocked.IsZero() {
+		t.Errorf("Expected First Steps to stay unlocked")
+	}
+
+	// Progress counts again from the next session
+	result := game.Result{ProblemType: problems.Daily, CorrectCount: 1, TotalCount: 20, CompletionTime: day.AddDate(0, 0, 3)}
+	if _, err := tracker.Record(result, nil); err != nil {
+		t.Fatal(err)
+	}
+	if streak := tracker.DailyStreak(day.AddDate(0, 0, 3)); streak != 1 {
+		t.Errorf("Expected a new streak of 1, got %d", streak)
+	}
+}
+
+func TestStreak(t *testing.T) {
+	tests := []struct {
+		days     []string
+		expected int
+	}{
+		{nil, 0},
+		{[]string{"2024-03-01"}, 1},
+		{[]string{"2024-02-28", "2024-02-29", "2024-03-01"}, 3},
+		{[]string{"2024-02-27", "2024-02-29", "2024-03-01"}, 2},
+	}
+
+	for _, test := range tests {
+		if got := Streak(test.days); got != test.expected {
+			t.Errorf("Streak(%v) = %d, expected %d", test.days, got, test.expected)
+		}
+	}
+}
+
+func TestMasteredFacts(t *testing.T) {
+	tracker, err := Load(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var answers []game.Answer
+	for a := 1; a <= 10; a++ {
+		for b := 1; b <= 10; b++ {
+			problem := problems.Problem{Type: problems.Multiplication, Operands: []int{a, b}, Answer: a * b}
+			answers = append(answers, game.Answer{Problem: problem, Correct: true, Hinted: a == 1 && b == 1})
+		}
+	}
+
+	result := game.Result{ProblemType: problems.Multiplication, CorrectCount: 100, TotalCount: 100, Duration: time.Hour}
+	badges, _ := tracker.Record(result, answers)
+	for _, badge := range badges {
+		if badge.ID == "times-table-master" {
+			t.Errorf("Hinted facts shouldn't count as mastered")
+		}
+	}
+
+	answers[0].Hinted = false
+	badges, _ = tracker.Record(result, answers[:1])
+	if len(badges) != 1 || badges[0].ID != "times-table-master" {
+		t.Errorf("Expected Times Table Master, got %v", badges)
+	}
+}
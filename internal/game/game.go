@@ -1,18 +1,109 @@
 package game
 
 import (
+	"fmt"
+	"slices"
 	"time"
 
 	"math-game/internal/problems"
 )
 
+// RetryPolicy says what happens after a wrong answer
+type RetryPolicy int
+
+const (
+	// NoRetry makes every answer final
+	NoRetry RetryPolicy = iota
+
+	// RetryOnce gives one more try straight away
+	RetryOnce
+
+	// RequeueMissed asks missed problems again at the end of the session
+	RequeueMissed
+)
+
+// retryPolicyNames maps each retry policy to the name used on the command line
+var retryPolicyNames = map[RetryPolicy]string{
+	NoRetry:       "none",
+	RetryOnce:     "once",
+	RequeueMissed: "requeue",
+}
+
+// String returns the name of the retry policy
+func (p RetryPolicy) String() string {
+	return retryPolicyNames[p]
+}
+
+// Set sets the retry policy from its name, so it can be used as a flag
+func (p *RetryPolicy) Set(name string) error {
+	policy, err := ParseRetryPolicy(name)
+	if err != nil {
+		return err
+	}
+	*p = policy
+	return nil
+}
+
+// ParseRetryPolicy returns the retry policy with the given name
+func ParseRetryPolicy(name string) (RetryPolicy, error) {
+	for policy, policyName := range retryPolicyNames {
+		if policyName == name {
+			return policy, nil
+		}
+	}
+	return NoRetry, fmt.Errorf("unknown retry policy %q: use none, once or requeue", name)
+}
+
 // Result represents the outcome of a game session
 type Result struct {
-	ProblemType    problems.ProblemType
-	CorrectCount   int
+	ProblemType problems.ProblemType
+
+	// CorrectCount counts the problems answered correctly at the first try
+	CorrectCount int
+
+	// EventuallyCorrect counts the problems answered correctly at the first
+	// try or on a retry
+	EventuallyCorrect int
+
 	TotalCount     int
 	Duration       time.Duration
 	CompletionTime time.Time
+
+	// Tables lists the times tables practiced, if only some were chosen
+	Tables []int
+
+	// TableScores counts the first-try answers to each times table, for
+	// multiplication and division sessions
+	TableScores []TableScore
+
+	// HintsUsed counts the problems the player asked for a hint on
+	HintsUsed int
+
+	// Score is the points earned, under the rules named by ScoringVersion
+	Score          int
+	ScoringVersion string
+
+	// BestStreak is the most correct answers given in a row
+	BestStreak int
+
+	// Badges names the achievements unlocked by this session
+	Badges []string
+
+	// Tier is the difficulty tier played, counting from 1, or 0 if the
+	// settings weren't chosen by tier
+	Tier int
+
+	// XP and Level are the experience earned and the player's level after
+	// the session; LevelUp is set if the session reached a new level
+	XP      int
+	Level   int
+	LevelUp bool
+
+	// Unlocks names the difficulty tiers unlocked by this session
+	Unlocks []string
+
+	// GoalsMet describes the practice goals this session completed
+	GoalsMet []string
 }
 
 // PercentCorrect returns the percentage of correct answers
@@ -23,6 +114,73 @@ func (r Result) PercentCorrect() float64 {
 	return float64(r.CorrectCount) / float64(r.TotalCount) * 100
 }
 
+// TableScore counts the first-try answers to the facts of one times table
+type TableScore struct {
+	Table   int
+	Correct int
+	Total   int
+}
+
+// Percent returns the percentage of the table's facts answered correctly
+func (s TableScore) Percent() float64 {
+	if s.Total == 0 {
+		return 0
+	}
+	return float64(s.Correct) / float64(s.Total) * 100
+}
+
+// TableScores counts the first-try answers to multiplication and division
+// facts by times table, in table order. A fact counts towards each of its
+// tables, so 6 × 7 counts for the 6s and the 7s, unless tables lists the
+// ones practiced, in which case only those are counted.
+func TableScores(answers []Answer, tables []int) []TableScore {
+	counts := make(map[int]*TableScore)
+	for _, answer := range answers {
+		for _, table := range problems.FactTables(answer.Problem) {
+			if len(tables) > 0 && !slices.Contains(tables, table) {
+				continue
+			}
+			score := counts[table]
+			if score == nil {
+				score = &TableScore{Table: table}
+				counts[table] = score
+			}
+			score.Total++
+			if answer.Correct {
+				score.Correct++
+			}
+		}
+	}
+	return sortedTableScores(counts)
+}
+
+// MergeTableScores adds up the table scores of several sessions
+func MergeTableScores(results []Result) []TableScore {
+	counts := make(map[int]*TableScore)
+	for _, result := range results {
+		for _, s := range result.TableScores {
+			score := counts[s.Table]
+			if score == nil {
+				score = &TableScore{Table: s.Table}
+				counts[s.Table] = score
+			}
+			score.Correct += s.Correct
+			score.Total += s.Total
+		}
+	}
+	return sortedTableScores(counts)
+}
+
+// sortedTableScores lists table scores in table order
+func sortedTableScores(counts map[int]*TableScore) []TableScore {
+	scores := make([]TableScore, 0, len(counts))
+	for _, score := range counts {
+		scores = append(scores, *score)
+	}
+	slices.SortFunc(scores, func(a, b TableScore) int { return a.Table - b.Table })
+	return scores
+}
+
 // Session represents a single game session
 type Session struct {
 	ProblemType   problems.ProblemType
@@ -30,7 +188,17 @@ type Session struct {
 	TotalProblems int
 	StartTime     time.Time
 	EndTime       time.Time
-	Answers       []bool // true for correct, false for incorrect
+	Answers       []Answer
+	Corrected     int // problems missed at first but answered correctly on a retry
+	HintsUsed     int
+
+	// Difficulty scales the points for each answer, from 1 (easiest) upwards
+	Difficulty int
+
+	// Scorer computes the session's points; the default rules are used if nil
+	Scorer Scorer
+
+	problemStart time.Time
 }
 
 // NewSession creates a new game session with the given problem generator
@@ -39,7 +207,8 @@ func NewSession(generator problems.Generator, totalProblems int) *Session {
 		ProblemType:   generator.Type(),
 		Generator:     generator,
 		TotalProblems: totalProblems,
-		Answers:       make([]bool, 0, totalProblems),
+		Answers:       make([]Answer, 0, totalProblems),
+		Difficulty:    1,
 	}
 }
 
@@ -61,16 +230,42 @@ func (s *Session) Duration() time.Duration {
 	return s.EndTime.Sub(s.StartTime)
 }
 
+// StartProblem marks the moment a problem is shown, to time the answer
+func (s *Session) StartProblem() {
+	s.problemStart = time.Now()
+}
+
 // AddResult records the result of a single problem (correct or incorrect)
-func (s *Session) AddResult(correct bool) {
-	s.Answers = append(s.Answers, correct)
+// and how long it took since StartProblem
+func (s *Session) AddResult(problem problems.Problem, correct bool) {
+	var elapsed time.Duration
+	if !s.problemStart.IsZero() {
+		elapsed = time.Since(s.problemStart)
+	}
+	s.Answers = append(s.Answers, Answer{Problem: problem, Correct: correct, Time: elapsed})
+}
+
+// AddRetry records the result of retrying a problem that was answered
+// incorrectly at the first try
+func (s *Session) AddRetry(correct bool) {
+	if correct {
+		s.Corrected++
+	}
+}
+
+// AddHint records that a hint was shown for the last problem answered
+func (s *Session) AddHint() {
+	s.HintsUsed++
+	if len(s.Answers) > 0 {
+		s.Answers[len(s.Answers)-1].Hinted = true
+	}
 }
 
 // CorrectCount returns the number of correct answers
 func (s *Session) CorrectCount() int {
 	count := 0
-	for _, correct := range s.Answers {
-		if correct {
+	for _, answer := range s.Answers {
+		if answer.Correct {
 			count++
 		}
 	}
@@ -79,11 +274,21 @@ func (s *Session) CorrectCount() int {
 
 // GetResult returns the final result of the session
 func (s *Session) GetResult() Result {
+	scorer := s.Scorer
+	if scorer == nil {
+		scorer, _ = LookupScorer(DefaultScoring)
+	}
+
 	return Result{
-		ProblemType:    s.ProblemType,
-		CorrectCount:   s.CorrectCount(),
-		TotalCount:     len(s.Answers),
-		Duration:       s.Duration(),
-		CompletionTime: s.EndTime,
+		ProblemType:       s.ProblemType,
+		CorrectCount:      s.CorrectCount(),
+		EventuallyCorrect: s.CorrectCount() + s.Corrected,
+		TotalCount:        len(s.Answers),
+		Duration:          s.Duration(),
+		CompletionTime:    s.EndTime,
+		HintsUsed:         s.HintsUsed,
+		Score:             scorer.Score(s.Answers, s.Difficulty),
+		ScoringVersion:    scorer.Version(),
+		BestStreak:        BestStreak(s.Answers),
 	}
 }
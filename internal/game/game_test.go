@@ -0,0 +1,178 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+
+	"math-game/internal/problems"
+)
+
+// times makes the fact a × b
+func times(a, b int) problems.Problem {
+	return problems.Problem{Type: problems.Multiplication, Operands: []int{a, b}, Answer: a * b}
+}
+
+// divide makes the fact (a × b) ÷ a
+func divide(a, b int) problems.Problem {
+	return problems.Problem{Type: problems.Division, Operands: []int{a * b, a}, Answer: b}
+}
+
+func TestTableScores(t *testing.T) {
+	mixed := []Answer{
+		{Problem: times(6, 3), Correct: true},
+		{Problem: times(4, 7), Correct: false},
+		{Problem: times(8, 8), Correct: true},
+		{Problem: times(6, 7), Correct: true},
+	}
+	tests := []struct {
+		name     string
+		answers  []Answer
+		tables   []int
+		expected []TableScore
+	}{
+		{"chosen tables only", mixed, []int{6, 7, 8}, []TableScore{
+			{Table: 6, Correct: 2, Total: 2},
+			{Table: 7, Correct: 1, Total: 2},
+			{Table: 8, Correct: 1, Total: 1},
+		}},
+		{"all tables", mixed, nil, []TableScore{
+			{Table: 3, Correct: 1, Total: 1},
+			{Table: 4, Correct: 0, Total: 1},
+			{Table: 6, Correct: 2, Total: 2},
+			{Table: 7, Correct: 1, Total: 2},
+			{Table: 8, Correct: 1, Total: 1},
+		}},
+		{"division", []Answer{
+			{Problem: divide(9, 7), Correct: true},
+			{Problem: divide(7, 9), Correct: false},
+		}, []int{9}, []TableScore{{Table: 9, Correct: 1, Total: 2}}},
+		{"other problems are skipped", []Answer{
+			{Problem: problems.Problem{Type: problems.Addition, Operands: []int{6, 7}, Answer: 13}, Correct: true},
+		}, nil, []TableScore{}},
+	}
+
+	for _, test := range tests {
+		if got := TableScores(test.answers, test.tables); !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, got)
+		}
+	}
+}
+
+func TestMergeTableScores(t *testing.T) {
+	results := []Result{
+		{TableScores: []TableScore{{Table: 6, Correct: 4, Total: 5}, {Table: 7, Correct: 1, Total: 5}}},
+		{},
+		{TableScores: []TableScore{{Table: 7, Correct: 5, Total: 5}}},
+	}
+
+	expected := []TableScore{{Table: 6, Correct: 4, Total: 5}, {Table: 7, Correct: 6, Total: 10}}
+	got := MergeTableScores(results)
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+	if percent := got[1].Percent(); percent != 60 {
+		t.Errorf("Expected 60%% for the 7s, got %.1f", percent)
+	}
+}
+
+func TestParseRetryPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected RetryPolicy
+		wantErr  bool
+	}{
+		{"none", NoRetry, false},
+		{"once", RetryOnce, false},
+		{"requeue", RequeueMissed, false},
+		{"twice", NoRetry, true},
+		{"", NoRetry, true},
+	}
+
+	for _, test := range tests {
+		got, err := ParseRetryPolicy(test.name)
+		if (err != nil) != test.wantErr {
+			t.Errorf("%q: expected error %v, got %v", test.name, test.wantErr, err)
+		}
+		if got != test.expected {
+			t.Errorf("%q: expected %v, got %v", test.name, test.expected, got)
+		}
+		if err == nil && got.String() != test.name {
+			t.Errorf("%q: expected the name back, got %q", test.name, got.String())
+		}
+	}
+}
+
+func TestRetryPolicySet(t *testing.T) {
+	policy := NoRetry
+	if err := policy.Set("requeue"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if policy != RequeueMissed {
+		t.Errorf("Expected %v, got %v", RequeueMissed, policy)
+	}
+
+	// An unknown name is an error and leaves the policy as it was
+	if err := policy.Set("always"); err == nil {
+		t.Error("Expected an error for an unknown policy")
+	}
+	if policy != RequeueMissed {
+		t.Errorf("Expected %v to be kept, got %v", RequeueMissed, policy)
+	}
+}
+
+func TestEventuallyCorrect(t *testing.T) {
+	tests := []struct {
+		name              string
+		firstTry          []bool
+		retries           []bool
+		correct, eventual int
+	}{
+		{"no retries", []bool{true, false, true}, nil, 2, 2},
+		{"retry fixes a miss", []bool{true, false, true}, []bool{true}, 2, 3},
+		{"retry missed again", []bool{false, false}, []bool{false, true}, 0, 1},
+		{"all fixed", []bool{false, false}, []bool{true, true}, 0, 2},
+	}
+
+	for _, test := range tests {
+		session := &Session{}
+		for _, correct := range test.firstTry {
+			session.AddResult(times(3, 4), correct)
+		}
+		for _, correct := range test.retries {
+			session.AddRetry(correct)
+		}
+
+		result := session.GetResult()
+		if result.CorrectCount != test.correct || result.EventuallyCorrect != test.eventual {
+			t.Errorf("%s: expected %d right first time and %d in the end, got %d and %d",
+				test.name, test.correct, test.eventual, result.CorrectCount, result.EventuallyCorrect)
+		}
+		if result.TotalCount != len(test.firstTry) {
+			t.Errorf("%s: expected retries not to add problems, got %d", test.name, result.TotalCount)
+		}
+	}
+}
+
+func TestAddHint(t *testing.T) {
+	session := &Session{Difficulty: 1}
+
+	// A hint before any answer is counted without marking one
+	session.AddHint()
+	session.AddResult(times(7, 8), true)
+	session.AddResult(times(6, 8), true)
+	session.AddHint()
+
+	if session.HintsUsed != 2 {
+		t.Errorf("Expected 2 hints used, got %d", session.HintsUsed)
+	}
+	if session.Answers[0].Hinted || !session.Answers[1].Hinted {
+		t.Errorf("Expected only the last answer to be hinted, got %v and %v",
+			session.Answers[0].Hinted, session.Answers[1].Hinted)
+	}
+
+	// The hinted answer earns half of its 11 points, streak bonus included
+	result := session.GetResult()
+	if result.Score != 10+5 {
+		t.Errorf("Expected 15 points, got %d", result.Score)
+	}
+}
@@ -0,0 +1,120 @@
+package game
+
+import (
+	"fmt"
+	"time"
+
+	"math-game/internal/problems"
+)
+
+// Answer is the outcome of one problem in a session
+type Answer struct {
+	Problem problems.Problem
+
+	// Correct is true if the problem was answered correctly at the first try
+	Correct bool
+
+	// Hinted is true if the player asked for a hint on the problem
+	Hinted bool
+
+	// Time is how long the player took to give their first answer
+	Time time.Duration
+}
+
+// Scorer turns the answers of a session into points. Each set of rules has
+// its own version, saved with the score, so that a change to the rules
+// gets a new version instead of changing the meaning of old scores.
+type Scorer interface {
+	// Version identifies the rules, e.g. "standard-v1"
+	Version() string
+
+	// Score returns the points for a session's answers at a difficulty
+	// from 1 (easiest) upwards
+	Score(answers []Answer, difficulty int) int
+}
+
+// DefaultScoring is the version of the scoring rules used for new sessions
+const DefaultScoring = "standard-v1"
+
+// scorers holds the registered scoring rules by version
+var scorers = map[string]Scorer{}
+
+// RegisterScorer adds a set of scoring rules. It panics if the version is
+// already registered, since that is a programming error.
+func RegisterScorer(scorer Scorer) {
+	if _, ok := scorers[scorer.Version()]; ok {
+		panic(fmt.Sprintf("game: scoring %q registered twice", scorer.Version()))
+	}
+	scorers[scorer.Version()] = scorer
+}
+
+// LookupScorer returns the scoring rules with the given version
+func LookupScorer(version string) (Scorer, bool) {
+	scorer, ok := scorers[version]
+	return scorer, ok
+}
+
+func init() {
+	RegisterScorer(StandardScorer{})
+}
+
+// StandardScorer is the first version of the scoring rules:
+//   - each correct answer is worth 10 points times the difficulty
+//   - timed answers within 5 seconds earn half as much again, and within
+//     10 seconds a quarter as much again
+//   - every correct answer in a row after the first adds 10% to the points
+//     for that answer, up to 50%
+//   - answers given after a hint earn half points
+//
+// Wrong answers score nothing but don't take points away.
+type StandardScorer struct{}
+
+// Version returns the version of the standard rules
+func (StandardScorer) Version() string {
+	return "standard-v1"
+}
+
+// Score returns the points for a session's answers
+func (StandardScorer) Score(answers []Answer, difficulty int) int {
+	total := 0
+	streak := 0
+	for _, answer := range answers {
+		if !answer.Correct {
+			streak = 0
+			continue
+		}
+		streak++
+
+		base := 10 * max(difficulty, 1)
+		points := base
+		switch {
+		case answer.Time == 0:
+			// Untimed answers get no speed bonus
+		case answer.Time <= 5*time.Second:
+			points += base / 2
+		case answer.Time <= 10*time.Second:
+			points += base / 4
+		}
+
+		points = points * (10 + min(streak-1, 5)) / 10
+		if answer.Hinted {
+			points /= 2
+		}
+		total += points
+	}
+	return total
+}
+
+// BestStreak returns the most correct answers given in a row
+func BestStreak(answers []Answer) int {
+	best, streak := 0, 0
+	for _, answer := range answers {
+		if answer.Correct {
+			streak++
+			best = max(best, streak)
+		} else {
+			streak = 0
+		}
+	}
+	return best
+}
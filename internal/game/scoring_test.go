@@ -0,0 +1,69 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"math-game/internal/problems"
+)
+
+func TestStandardScorer(t *testing.T) {
+	slow := 30 * time.Second
+	tests := []struct {
+		name       string
+		answers    []Answer
+		difficulty int
+		expected   int
+	}{
+		{"one slow answer", []Answer{{Correct: true, Time: slow}}, 1, 10},
+		{"difficulty scales points", []Answer{{Correct: true, Time: slow}}, 3, 30},
+		{"fast answer", []Answer{{Correct: true, Time: 3 * time.Second}}, 2, 30},
+		{"quick answer", []Answer{{Correct: true, Time: 8 * time.Second}}, 4, 50},
+		{"wrong answers score nothing", []Answer{{Correct: false, Time: time.Second}}, 1, 0},
+		{"hint halves points", []Answer{{Correct: true, Hinted: true, Time: slow}}, 2, 10},
+		{"streak", []Answer{
+			{Correct: true, Time: slow}, {Correct: true, Time: slow}, {Correct: true, Time: slow},
+		}, 1, 10 + 11 + 12},
+		{"streak broken", []Answer{
+			{Correct: true, Time: slow}, {Correct: true, Time: slow}, {Correct: false}, {Correct: true, Time: slow},
+		}, 1, 10 + 11 + 10},
+		{"streak bonus is capped", []Answer{
+			{Correct: true, Time: slow}, {Correct: true, Time: slow}, {Correct: true, Time: slow},
+			{Correct: true, Time: slow}, {Correct: true, Time: slow}, {Correct: true, Time: slow},
+			{Correct: true, Time: slow},
+		}, 1, 10 + 11 + 12 + 13 + 14 + 15 + 15},
+	}
+
+	scorer, ok := LookupScorer(DefaultScoring)
+	if !ok {
+		t.Fatalf("Default scoring %q is not registered", DefaultScoring)
+	}
+	for _, test := range tests {
+		if got := scorer.Score(test.answers, test.difficulty); got != test.expected {
+			t.Errorf("%s: expected %d points, got %d", test.name, test.expected, got)
+		}
+	}
+}
+
+func TestSessionResultScore(t *testing.T) {
+	session := &Session{Difficulty: 2}
+	problem := problems.Problem{Question: "3 × 4", Answer: 12}
+	session.AddResult(problem, true)
+	session.AddResult(problem, true)
+	session.AddHint()
+	session.AddResult(problem, false)
+	session.AddRetry(true)
+
+	result := session.GetResult()
+	if result.CorrectCount != 2 || result.EventuallyCorrect != 3 {
+		t.Errorf("Expected 2 right first time and 3 in the end, got %d and %d", result.CorrectCount, result.EventuallyCorrect)
+	}
+	if result.ScoringVersion != DefaultScoring || result.BestStreak != 2 || result.HintsUsed != 1 {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+
+	// Without StartProblem there is no speed bonus: 20, then 20 × 1.1 / 2
+	if result.Score != 20+11 {
+		t.Errorf("Expected 31 points, got %d", result.Score)
+	}
+}
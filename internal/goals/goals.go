@@ -0,0 +1,236 @@
+package goals
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"math-game/internal/game"
+	"math-game/internal/problems"
+)
+
+// Period is how often a goal starts over
+type Period string
+
+const (
+	Daily  Period = "day"
+	Weekly Period = "week"
+)
+
+// ParsePeriod reads a period from its name
+func ParsePeriod(s string) (Period, error) {
+	switch Period(s) {
+	case Daily, Weekly:
+		return Period(s), nil
+	default:
+		return "", fmt.Errorf("unknown period %q: use day or week", s)
+	}
+}
+
+// key names the period that t falls in, such as "2024-03-01" for a day or
+// "2024-W09" for a week
+func (p Period) key(t time.Time) string {
+	if p == Weekly {
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	}
+	return t.Format("2006-01-02")
+}
+
+// Goal is a practice target set by a parent, such as 3 multiplication
+// sessions a week at 90% or more, or 10 minutes a day. Exactly one of
+// Sessions and Minutes is set.
+type Goal struct {
+	// ProblemType limits the goal to one game, or any game if empty
+	ProblemType problems.ProblemType
+
+	Sessions int
+	Minutes  int
+
+	// MinPercent is the score a session needs to count, if set
+	MinPercent int
+
+	Per Period
+
+	// Current names the period that Progress and Met belong to. Progress
+	// counts sessions, or seconds of practice for a Minutes goal.
+	Current  string
+	Progress int
+	Met      time.Time
+}
+
+// Validate checks that the goal has a target, a period and a known game
+func (g Goal) Validate() error {
+	if (g.Sessions > 0) == (g.Minutes > 0) {
+		return fmt.Errorf("a goal needs either a number of sessions or of minutes")
+	}
+	if g.Sessions < 0 || g.Minutes < 0 {
+		return fmt.Errorf("a goal's target can't be negative")
+	}
+	if g.MinPercent < 0 || g.MinPercent > 100 {
+		return fmt.Errorf("a goal's score must be between 0 and 100")
+	}
+	if _, err := ParsePeriod(string(g.Per)); err != nil {
+		return err
+	}
+	if g.ProblemType != "" && g.ProblemType != problems.Daily {
+		if _, ok := problems.Lookup(g.ProblemType); !ok {
+			return fmt.Errorf("unknown game type %q", g.ProblemType)
+		}
+	}
+	return nil
+}
+
+// String describes the goal, e.g. "3 Multiplication sessions a week at 90%
+// or more"
+func (g Goal) String() string {
+	name := ""
+	if g.ProblemType != "" {
+		name = problems.DisplayName(g.ProblemType) + " "
+	}
+
+	var s string
+	if g.Minutes > 0 {
+		s = fmt.Sprintf("%d minutes of %spractice a %s", g.Minutes, name, g.Per)
+	} else {
+		noun := "sessions"
+		if g.Sessions == 1 {
+			noun = "session"
+		}
+		s = fmt.Sprintf("%d %s%s a %s", g.Sessions, name, noun, g.Per)
+	}
+	if g.MinPercent > 0 {
+		s += fmt.Sprintf(" at %d%% or more", g.MinPercent)
+	}
+	return s
+}
+
+// Status returns how far the goal has got in the period containing now and
+// its target, in sessions or minutes, and whether it has been met
+func (g Goal) Status(now time.Time) (progress, target int, met bool) {
+	target = g.Sessions
+	if g.Minutes > 0 {
+		target = g.Minutes
+	}
+	if g.Current != g.Per.key(now) {
+		return 0, target, false
+	}
+
+	progress = g.Progress
+	if g.Minutes > 0 {
+		progress /= 60
+	}
+	return progress, target, !g.Met.IsZero()
+}
+
+// counts reports whether a session's result counts towards the goal
+func (g Goal) counts(result game.Result) bool {
+	if g.ProblemType != "" && result.ProblemType != g.ProblemType {
+		return false
+	}
+	return result.PercentCorrect() >= float64(g.MinPercent)
+}
+
+// Tracker keeps the goals of one profile in a file
+type Tracker struct {
+	path  string
+	goals []Goal
+}
+
+// Load reads the goals kept in dir. A missing file means no goals have been
+// set.
+func Load(dir string) (*Tracker, error) {
+	t := &Tracker{path: filepath.Join(dir, "goals.json")}
+
+	data, err := os.ReadFile(t.path)
+	if os.IsNotExist(err) {
+		return t, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read goals: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &t.goals); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal goals: %w", err)
+	}
+	return t, nil
+}
+
+// Goals returns every goal in the order they were added
+func (t *Tracker) Goals() []Goal {
+	return append([]Goal(nil), t.goals...)
+}
+
+// Add checks a new goal and saves it
+func (t *Tracker) Add(goal Goal) error {
+	if err := goal.Validate(); err != nil {
+		return err
+	}
+	goal.Current, goal.Progress, goal.Met = "", 0, time.Time{}
+	t.goals = append(t.goals, goal)
+	return t.save()
+}
+
+// Remove deletes the goal at index, counting from 0, and saves the change
+func (t *Tracker) Remove(index int) error {
+	if index < 0 || index >= len(t.goals) {
+		return fmt.Errorf("there is no goal %d", index+1)
+	}
+	t.goals = append(t.goals[:index], t.goals[index+1:]...)
+	return t.save()
+}
+
+// Record adds a finished session to the progress of every goal it counts
+// towards, saves it, and returns the goals the session met
+func (t *Tracker) Record(result game.Result) ([]Goal, error) {
+	if len(t.goals) == 0 {
+		return nil, nil
+	}
+
+	finished := result.CompletionTime
+	if finished.IsZero() {
+		finished = time.Now()
+	}
+
+	var met []Goal
+	for i := range t.goals {
+		goal := &t.goals[i]
+
+		// Start over in a new period
+		if key := goal.Per.key(finished); goal.Current != key {
+			goal.Current, goal.Progress, goal.Met = key, 0, time.Time{}
+		}
+		if !goal.counts(result) {
+			continue
+		}
+
+		if goal.Minutes > 0 {
+			goal.Progress += int(result.Duration / time.Second)
+		} else {
+			goal.Progress++
+		}
+
+		progress, target, _ := goal.Status(finished)
+		if goal.Met.IsZero() && progress >= target {
+			goal.Met = finished
+			met = append(met, *goal)
+		}
+	}
+
+	return met, t.save()
+}
+
+// save writes the goals file
+func (t *Tracker) save() error {
+	data, err := json.MarshalIndent(t.goals, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal goals: %w", err)
+	}
+
+	if err := os.WriteFile(t.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write goals: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package goals
+
+import (
+	"testing"
+	"time"
+
+	"math-game/internal/game"
+	"math-game/internal/problems"
+)
+
+func TestRecordMeetsGoals(t *testing.T) {
+	dir := t.TempDir()
+	tracker, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Failed to load: %v", err)
+	}
+
+	weekly := Goal{ProblemType: problems.Multiplication, Sessions: 2, MinPercent: 90, Per: Weekly}
+	daily := Goal{Minutes: 10, Per: Daily}
+	for _, goal := range []Goal{weekly, daily} {
+		if err := tracker.Add(goal); err != nil {
+			t.Fatalf("Failed to add %s: %v", goal, err)
+		}
+	}
+
+	// Friday 1 March 2024 and the Saturday after are in the same week
+	friday := time.Date(2024, 3, 1, 16, 0, 0, 0, time.Local)
+	session := func(problemType problems.ProblemType, correct int, minutes int, when time.Time) []Goal {
+		t.Helper()
+		met, err := tracker.Record(game.Result{
+			ProblemType:    problemType,
+			CorrectCount:   correct,
+			TotalCount:     20,
+			Duration:       time.Duration(minutes) * time.Minute,
+			CompletionTime: when,
+		})
+		if err != nil {
+			t.Fatalf("Failed to record: %v", err)
+		}
+		return met
+	}
+
+	if met := session(problems.Multiplication, 19, 4, friday); len(met) != 0 {
+		t.Errorf("Expected no goals met, got %v", met)
+	}
+	// A low score doesn't count towards the weekly goal, but its time does
+	// count towards the daily one
+	if met := session(problems.Multiplication, 10, 6, friday.Add(time.Hour)); len(met) != 1 || met[0].Minutes != 10 {
+		t.Errorf("Expected the daily goal met, got %v", met)
+	}
+	if met := session(problems.Multiplication, 20, 1, friday.AddDate(0, 0, 1)); len(met) != 1 || met[0].Sessions != 2 {
+		t.Errorf("Expected the weekly goal met, got %v", met)
+	}
+
+	// Progress is kept in the profile and starts over each period
+	tracker, err = Load(dir)
+	if err != nil {
+		t.Fatalf("Failed to reload: %v", err)
+	}
+	saved := tracker.Goals()
+	if progress, target, met := saved[0].Status(friday.AddDate(0, 0, 1)); progress != 2 || target != 2 || !met {
+		t.Errorf("Expected the weekly goal met at 2/2, got %d/%d, %v", progress, target, met)
+	}
+	if progress, _, met := saved[0].Status(friday.AddDate(0, 0, 7)); progress != 0 || met {
+		t.Errorf("Expected the weekly goal to start over the next week, got %d, %v", progress, met)
+	}
+	if progress, _, met := saved[1].Status(friday.AddDate(0, 0, 1)); progress != 1 || met {
+		t.Errorf("Expected 1 minute towards the daily goal on Saturday, got %d, %v", progress, met)
+	}
+}
+
+func TestGoalValidate(t *testing.T) {
+	tests := []struct {
+		goal  Goal
+		valid bool
+	}{
+		{Goal{Sessions: 3, Per: Weekly}, true},
+		{Goal{Minutes: 10, Per: Daily, ProblemType: problems.Addition}, true},
+		{Goal{Sessions: 3, Minutes: 10, Per: Weekly}, false},
+		{Goal{Per: Weekly}, false},
+		{Goal{Sessions: 3, Per: "month"}, false},
+		{Goal{Sessions: 3, Per: Weekly, MinPercent: 120}, false},
+		{Goal{Sessions: 3, Per: Weekly, ProblemType: "unknown"}, false},
+	}
+
+	for _, tt := range tests {
+		if err := tt.goal.Validate(); (err == nil) != tt.valid {
+			t.Errorf("%+v: expected valid %v, got error %v", tt.goal, tt.valid, err)
+		}
+	}
+}
@@ -15,6 +15,7 @@ import (
 type Storage interface {
 	SaveResult(result game.Result) error
 	GetResults(problemType problems.ProblemType, limit int) ([]game.Result, error)
+	Clear(problemType problems.ProblemType) error
 }
 
 // FileStorage implements history storage using files
@@ -25,7 +26,7 @@ type FileStorage struct {
 // NewFileStorage creates a new file-based storage for game history
 func NewFileStorage(baseDir string) (*FileStorage, error) {
 	if _, err := os.Stat(baseDir); os.IsNotExist(err) {
-		if err := os.MkdirAll(baseDir, 0755); err != nil {
+		if err := os.MkdirAll(baseDir, 0700); err != nil {
 			return nil, fmt.Errorf("failed to create history directory: %w", err)
 		}
 	}
@@ -69,7 +70,7 @@ func (s *FileStorage) SaveResult(result game.Result) error {
 		return fmt.Errorf("failed to marshal results: %w", err)
 	}
 
-	if err := os.WriteFile(filePath, data, 0644); err != nil {
+	if err := os.WriteFile(filePath, data, 0600); err != nil {
 		return fmt.Errorf("failed to write results file: %w", err)
 	}
 
@@ -109,3 +110,12 @@ func (s *FileStorage) GetResults(problemType problems.ProblemType, limit int) ([
 
 	return results, nil
 }
+
+// Clear deletes every saved result for a problem type
+func (s *FileStorage) Clear(problemType problems.ProblemType) error {
+	err := os.Remove(s.getFilePath(problemType))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete results file: %w", err)
+	}
+	return nil
+}
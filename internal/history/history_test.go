@@ -0,0 +1,65 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"math-game/internal/game"
+	"math-game/internal/problems"
+)
+
+func TestClear(t *testing.T) {
+	storage, err := NewFileStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	for _, problemType := range []problems.ProblemType{problems.Addition, problems.Subtraction} {
+		if err := storage.SaveResult(game.Result{ProblemType: problemType, TotalCount: 20, CompletionTime: time.Now()}); err != nil {
+			t.Fatalf("Failed to save: %v", err)
+		}
+	}
+
+	if err := storage.Clear(problems.Addition); err != nil {
+		t.Fatalf("Failed to clear: %v", err)
+	}
+	if results, _ := storage.GetResults(problems.Addition, 0); len(results) != 0 {
+		t.Errorf("Expected cleared history to be empty, got %d results", len(results))
+	}
+	if results, _ := storage.GetResults(problems.Subtraction, 0); len(results) != 1 {
+		t.Errorf("Expected other history to be kept, got %d results", len(results))
+	}
+
+	// Clearing a game with no history is not an error
+	if err := storage.Clear(problems.Division); err != nil {
+		t.Errorf("Expected no error clearing empty history, got %v", err)
+	}
+}
+
+func TestPermissions(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "history")
+	storage, err := NewFileStorage(dir)
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	if err := storage.SaveResult(game.Result{ProblemType: problems.Addition, TotalCount: 20, CompletionTime: time.Now()}); err != nil {
+		t.Fatalf("Failed to save: %v", err)
+	}
+
+	// History is only readable by its owner
+	info, err := os.Stat(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0700 {
+		t.Errorf("Expected directory mode 0700, got %o", mode)
+	}
+	info, err = os.Stat(filepath.Join(dir, "addition.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0600 {
+		t.Errorf("Expected file mode 0600, got %o", mode)
+	}
+}
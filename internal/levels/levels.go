@@ -0,0 +1,187 @@
+package levels
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"math-game/internal/game"
+	"math-game/internal/history"
+	"math-game/internal/problems"
+)
+
+const (
+	// MasterySessions is how many sessions in a row at a tier must reach
+	// MasteryPercent to unlock the next tier
+	MasterySessions = 2
+
+	// MasteryPercent is the score that counts towards mastering a tier
+	MasteryPercent = 90
+)
+
+// XPForLevel returns the total XP needed to reach a level. Each level
+// needs 100 XP more than the one before it.
+func XPForLevel(level int) int {
+	return 100 * level * (level - 1) / 2
+}
+
+// LevelFor returns the level reached with the given XP
+func LevelFor(xp int) int {
+	level := 1
+	for XPForLevel(level+1) <= xp {
+		level++
+	}
+	return level
+}
+
+// Update is what changed when a session was recorded
+type Update struct {
+	XP       int // earned in the session
+	Level    int // reached after the session
+	LevelUp  bool
+	Unlocked []problems.Tier
+}
+
+// state is the contents of a profile's levels file
+type state struct {
+	XP int
+
+	// Tiers holds the highest tier unlocked for each problem type, counting
+	// from 1. Types that aren't listed have only their first tier unlocked.
+	Tiers map[problems.ProblemType]int
+
+	// Overrides lists the problem types a parent has unlocked every tier of
+	Overrides map[problems.ProblemType]bool
+}
+
+// Tracker keeps the XP and unlocked tiers of one profile in a file
+type Tracker struct {
+	path  string
+	state state
+}
+
+// Load reads the levels kept in dir. A missing file means a new player at
+// level 1.
+func Load(dir string) (*Tracker, error) {
+	t := &Tracker{path: filepath.Join(dir, "levels.json")}
+
+	data, err := os.ReadFile(t.path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read levels: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &t.state); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal levels: %w", err)
+		}
+	}
+
+	if t.state.Tiers == nil {
+		t.state.Tiers = map[problems.ProblemType]int{}
+	}
+	if t.state.Overrides == nil {
+		t.state.Overrides = map[problems.ProblemType]bool{}
+	}
+	return t, nil
+}
+
+// XP returns the total XP earned
+func (t *Tracker) XP() int {
+	return t.state.XP
+}
+
+// Level returns the player's level
+func (t *Tracker) Level() int {
+	return LevelFor(t.state.XP)
+}
+
+// Unlocked returns the highest tier of a problem type that can be played,
+// counting from 1
+func (t *Tracker) Unlocked(def problems.Definition) int {
+	if t.state.Overrides[def.Type] {
+		return len(def.Tiers)
+	}
+	return min(max(t.state.Tiers[def.Type], 1), len(def.Tiers))
+}
+
+// Overridden reports whether a parent has unlocked every tier of a type
+func (t *Tracker) Overridden(problemType problems.ProblemType) bool {
+	return t.state.Overrides[problemType]
+}
+
+// Override unlocks every tier of a problem type, or takes the override away
+// so only the tiers earned can be played, and saves the change
+func (t *Tracker) Override(problemType problems.ProblemType, unlocked bool) error {
+	if unlocked {
+		t.state.Overrides[problemType] = true
+	} else {
+		delete(t.state.Overrides, problemType)
+	}
+	return t.save()
+}
+
+// Record adds a session's points as XP and unlocks the next tier if the
+// session completes mastery of the highest tier unlocked so far. It must be
+// called before the result is saved to storage.
+func (t *Tracker) Record(result game.Result, storage history.Storage) (Update, error) {
+	before := t.Level()
+	t.state.XP += result.Score
+	update := Update{XP: result.Score, Level: t.Level()}
+	update.LevelUp = update.Level > before
+
+	def, ok := problems.Lookup(result.ProblemType)
+	if ok && result.Tier > 0 && result.Tier == max(t.state.Tiers[def.Type], 1) && result.Tier < len(def.Tiers) {
+		mastered, err := t.mastered(result, storage)
+		if err != nil {
+			return update, err
+		}
+		if mastered {
+			t.state.Tiers[def.Type] = result.Tier + 1
+			update.Unlocked = append(update.Unlocked, def.Tiers[result.Tier])
+		}
+	}
+
+	return update, t.save()
+}
+
+// mastered reports whether the result and the sessions before it at the
+// same tier complete MasterySessions in a row at MasteryPercent or better
+func (t *Tracker) mastered(result game.Result, storage history.Storage) (bool, error) {
+	past, err := storage.GetResults(result.ProblemType, 0)
+	if err != nil {
+		return false, err
+	}
+
+	recent := []game.Result{result}
+	for _, r := range past {
+		if len(recent) == MasterySessions {
+			break
+		}
+		if r.Tier == result.Tier {
+			recent = append(recent, r)
+		}
+	}
+	if len(recent) < MasterySessions {
+		return false, nil
+	}
+
+	for _, r := range recent {
+		if r.PercentCorrect() < MasteryPercent {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// save writes the levels file
+func (t *Tracker) save() error {
+	data, err := json.MarshalIndent(t.state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal levels: %w", err)
+	}
+
+	if err := os.WriteFile(t.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write levels: %w", err)
+	}
+	return nil
+}
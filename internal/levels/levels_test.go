@@ -0,0 +1,118 @@
+package levels
+
+import (
+	"testing"
+	"time"
+
+	"math-game/internal/game"
+	"math-game/internal/history"
+	"math-game/internal/problems"
+)
+
+func TestLevelFor(t *testing.T) {
+	tests := []struct {
+		xp    int
+		level int
+	}{
+		{0, 1},
+		{99, 1},
+		{100, 2},
+		{299, 2},
+		{300, 3},
+		{1000, 5},
+	}
+
+	for _, tt := range tests {
+		if got := LevelFor(tt.xp); got != tt.level {
+			t.Errorf("LevelFor(%d) = %d, want %d", tt.xp, got, tt.level)
+		}
+	}
+}
+
+func TestRecordUnlocksNextTier(t *testing.T) {
+	dir := t.TempDir()
+	storage, err := history.NewFileStorage(dir)
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	tracker, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Failed to load: %v", err)
+	}
+	def, _ := problems.Lookup(problems.Addition)
+	if got := tracker.Unlocked(def); got != 1 {
+		t.Fatalf("Expected only the first tier unlocked, got %d", got)
+	}
+
+	start := time.Date(2024, 3, 1, 16, 0, 0, 0, time.Local)
+	play := func(correct int, score int) Update {
+		t.Helper()
+		result := game.Result{
+			ProblemType:    problems.Addition,
+			CorrectCount:   correct,
+			TotalCount:     20,
+			CompletionTime: start,
+			Score:          score,
+			Tier:           1,
+		}
+		start = start.Add(time.Hour)
+		update, err := tracker.Record(result, storage)
+		if err != nil {
+			t.Fatalf("Failed to record: %v", err)
+		}
+		if err := storage.SaveResult(result); err != nil {
+			t.Fatalf("Failed to save: %v", err)
+		}
+		return update
+	}
+
+	// A session below the mastery score breaks the run
+	if update := play(20, 60); len(update.Unlocked) != 0 || update.Level != 1 {
+		t.Errorf("Expected no unlock at level 1, got %+v", update)
+	}
+	if update := play(15, 60); len(update.Unlocked) != 0 || !update.LevelUp || update.Level != 2 {
+		t.Errorf("Expected a level up to 2 without an unlock, got %+v", update)
+	}
+	if update := play(19, 60); len(update.Unlocked) != 0 {
+		t.Errorf("Expected no unlock after one mastered session, got %+v", update)
+	}
+	update := play(18, 60)
+	if len(update.Unlocked) != 1 || update.Unlocked[0].Name != def.Tiers[1].Name {
+		t.Errorf("Expected %q unlocked, got %+v", def.Tiers[1].Name, update)
+	}
+
+	// Progress is kept in the profile
+	tracker, err = Load(dir)
+	if err != nil {
+		t.Fatalf("Failed to reload: %v", err)
+	}
+	if got := tracker.Unlocked(def); got != 2 {
+		t.Errorf("Expected 2 tiers unlocked after reloading, got %d", got)
+	}
+	if got := tracker.XP(); got != 240 {
+		t.Errorf("Expected 240 XP after reloading, got %d", got)
+	}
+}
+
+func TestOverride(t *testing.T) {
+	dir := t.TempDir()
+	tracker, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Failed to load: %v", err)
+	}
+	def, _ := problems.Lookup(problems.Multiplication)
+
+	if err := tracker.Override(def.Type, true); err != nil {
+		t.Fatalf("Failed to override: %v", err)
+	}
+	if got := tracker.Unlocked(def); got != len(def.Tiers) {
+		t.Errorf("Expected every tier unlocked, got %d", got)
+	}
+
+	if err := tracker.Override(def.Type, false); err != nil {
+		t.Fatalf("Failed to override: %v", err)
+	}
+	if got := tracker.Unlocked(def); got != 1 {
+		t.Errorf("Expected only the first tier unlocked after locking, got %d", got)
+	}
+}
@@ -0,0 +1,114 @@
+package pin
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Iterations is how many times a PIN is hashed, to slow down guessing it
+// from a copy of the file
+const Iterations = 100000
+
+// saltSize is the number of random bytes hashed with each PIN
+const saltSize = 16
+
+// hashed is the contents of the PIN file. The PIN itself is never saved.
+type hashed struct {
+	Salt       []byte
+	Hash       []byte
+	Iterations int
+}
+
+// PIN is the parent's PIN, kept hashed in a file
+type PIN struct {
+	path   string
+	hashed *hashed
+}
+
+// Load reads the PIN kept in dir. A missing file means no PIN has been set.
+func Load(dir string) (*PIN, error) {
+	p := &PIN{path: filepath.Join(dir, "pin.json")}
+
+	data, err := os.ReadFile(p.path)
+	if os.IsNotExist(err) {
+		return p, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read PIN: %w", err)
+	}
+
+	p.hashed = &hashed{}
+	if err := json.Unmarshal(data, p.hashed); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal PIN: %w", err)
+	}
+	return p, nil
+}
+
+// IsSet reports whether a PIN has been set
+func (p *PIN) IsSet() bool {
+	return p.hashed != nil
+}
+
+// Valid checks that code is 4 to 8 digits
+func Valid(code string) error {
+	if len(code) < 4 || len(code) > 8 {
+		return fmt.Errorf("the PIN must be 4 to 8 digits")
+	}
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return fmt.Errorf("the PIN must be 4 to 8 digits")
+		}
+	}
+	return nil
+}
+
+// Set replaces the PIN with code and saves it
+func (p *PIN) Set(code string) error {
+	if err := Valid(code); err != nil {
+		return err
+	}
+
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return fmt.Errorf("failed to make salt: %w", err)
+	}
+	p.hashed = &hashed{Salt: salt, Hash: hash(code, salt, Iterations), Iterations: Iterations}
+	return p.save()
+}
+
+// Check reports whether code is the PIN. It is false if no PIN is set.
+func (p *PIN) Check(code string) bool {
+	if p.hashed == nil {
+		return false
+	}
+	got := hash(code, p.hashed.Salt, p.hashed.Iterations)
+	return subtle.ConstantTimeCompare(got, p.hashed.Hash) == 1
+}
+
+// hash hashes code with salt using SHA-256, feeding each hash back in for
+// the given number of iterations
+func hash(code string, salt []byte, iterations int) []byte {
+	sum := sha256.Sum256(append(append([]byte(nil), salt...), code...))
+	for i := 1; i < iterations; i++ {
+		sum = sha256.Sum256(append(sum[:], salt...))
+	}
+	return sum[:]
+}
+
+// save writes the PIN file, readable only by its owner
+func (p *PIN) save() error {
+	data, err := json.MarshalIndent(p.hashed, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal PIN: %w", err)
+	}
+
+	if err := os.WriteFile(p.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write PIN: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package pin
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSetAndCheck(t *testing.T) {
+	dir := t.TempDir()
+	p, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Failed to load: %v", err)
+	}
+	if p.IsSet() || p.Check("") {
+		t.Fatal("Expected no PIN before one is set")
+	}
+
+	for _, code := range []string{"123", "123456789", "12a4"} {
+		if err := p.Set(code); err == nil {
+			t.Errorf("Expected %q to be rejected", code)
+		}
+	}
+
+	if err := p.Set("2468"); err != nil {
+		t.Fatalf("Failed to set PIN: %v", err)
+	}
+
+	// The PIN survives reloading and is not saved in the clear
+	p, err = Load(dir)
+	if err != nil {
+		t.Fatalf("Failed to reload: %v", err)
+	}
+	if !p.IsSet() || !p.Check("2468") || p.Check("2469") {
+		t.Error("Expected only the PIN that was set to be accepted")
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "pin.json"))
+	if err != nil {
+		t.Fatalf("Failed to read PIN file: %v", err)
+	}
+	if bytes.Contains(data, []byte("2468")) {
+		t.Error("Expected the PIN file not to contain the PIN")
+	}
+	info, err := os.Stat(filepath.Join(dir, "pin.json"))
+	if err != nil {
+		t.Fatalf("Failed to stat PIN file: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Expected the PIN file to be private, got %v", info.Mode().Perm())
+	}
+}
+
+func TestHashIsSalted(t *testing.T) {
+	a := hash("2468", []byte("salt-one"), 10)
+	b := hash("2468", []byte("salt-two"), 10)
+	if bytes.Equal(a, b) {
+		t.Error("Expected different salts to give different hashes")
+	}
+	if !bytes.Equal(a, hash("2468", []byte("salt-one"), 10)) {
+		t.Error("Expected the same salt to give the same hash")
+	}
+}
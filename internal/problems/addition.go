@@ -34,6 +34,7 @@ func (g *AdditionGenerator) Generate() Problem {
 		Question: fmt.Sprintf("%d + %d", num1, num2),
 		Answer:   num1 + num2,
 		Type:     Addition,
+		Operands: []int{num1, num2},
 	}
 }
 
@@ -0,0 +1,191 @@
+package problems
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// AnswerKind identifies how a problem's answer is encoded in Problem.Answer
+// and how user input is parsed into it
+type AnswerKind string
+
+const (
+	// NumberAnswer is a whole or fixed-point decimal number
+	NumberAnswer AnswerKind = "number"
+
+	// ComparisonAnswer is one of <, = or >, encoded as -1, 0 or 1
+	ComparisonAnswer AnswerKind = "comparison"
+
+	// TimeAnswer is a time on a 12-hour clock, encoded as minutes past 12:00
+	TimeAnswer AnswerKind = "time"
+)
+
+// answerFormat parses and formats the answers of one kind
+type answerFormat struct {
+	// parse converts user input into an encoded answer
+	parse func(p Problem, input string) (int, error)
+
+	// format renders an encoded answer for display
+	format func(p Problem, answer int) string
+
+	// hint tells the user what kind of answer is expected, if not a number
+	hint string
+}
+
+// answerFormats holds the format for each answer kind
+var answerFormats = map[AnswerKind]answerFormat{
+	NumberAnswer: {
+		parse:  parseNumber,
+		format: formatNumber,
+	},
+	ComparisonAnswer: {
+		parse:  parseComparison,
+		format: formatComparison,
+		hint:   "<, > or =",
+	},
+	TimeAnswer: {
+		parse: func(p Problem, input string) (int, error) {
+			return ParseClockTime(input)
+		},
+		format: func(p Problem, answer int) string {
+			return FormatClockTime(answer)
+		},
+		hint: "H:MM",
+	},
+}
+
+// format returns the answer format for the problem's kind
+func (p Problem) format() answerFormat {
+	if f, ok := answerFormats[p.Kind]; ok {
+		return f
+	}
+	return answerFormats[NumberAnswer]
+}
+
+// ParseAnswer converts a user's input into the same representation as Answer
+func (p Problem) ParseAnswer(input string) (int, error) {
+	return p.format().parse(p, strings.TrimSpace(input))
+}
+
+// IsCorrect reports whether answer, as returned by ParseAnswer, solves the problem
+func (p Problem) IsCorrect(answer int) bool {
+	diff := answer - p.Answer
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff <= p.Tolerance
+}
+
+// FormatAnswer returns the correct answer as it should be shown to the user
+func (p Problem) FormatAnswer() string {
+	f := p.format()
+	if p.Tolerance > 0 {
+		return fmt.Sprintf("about %s (anything from %s to %s)",
+			f.format(p, p.Answer),
+			f.format(p, p.Answer-p.Tolerance),
+			f.format(p, p.Answer+p.Tolerance))
+	}
+	return f.format(p, p.Answer)
+}
+
+// parseNumber parses a whole number, or a decimal if the problem has
+// decimal places. Commas between thousands, as in "5,400", are ignored.
+// Problems with a unit also accept the unit after the number.
+func parseNumber(p Problem, input string) (int, error) {
+	input = strings.ReplaceAll(normalizeSign(input), ",", "")
+
+	if u, ok := LookupUnit(p.Unit); ok {
+		var err error
+		if input, err = stripUnit(input, u); err != nil {
+			return 0, err
+		}
+	}
+
+	if p.Decimals > 0 {
+		return ParseDecimal(input, p.Decimals)
+	}
+
+	answer, err := strconv.Atoi(input)
+	if err != nil {
+		return 0, fmt.Errorf("invalid input: %q is not a whole number", input)
+	}
+	return answer, nil
+}
+
+// formatNumber formats a whole or decimal number
+func formatNumber(p Problem, answer int) string {
+	if p.Thousands && p.Decimals == 0 {
+		return FormatThousands(answer)
+	}
+	if p.Type == Money {
+		return "$" + FormatDecimal(answer, p.Decimals)
+	}
+	if u, ok := LookupUnit(p.Unit); ok {
+		return u.Format(FormatDecimal(answer, p.Decimals), answer)
+	}
+	return FormatDecimal(answer, p.Decimals)
+}
+
+// stripUnit removes a trailing unit such as "in" or "inches" from input.
+// It's an error to answer in a different unit than the one asked for.
+func stripUnit(input string, u Unit) (string, error) {
+	i := strings.IndexFunc(input, func(r rune) bool {
+		return !(r >= '0' && r <= '9') && !strings.ContainsRune("-.$", r)
+	})
+	if i < 0 {
+		return input, nil
+	}
+
+	number, suffix := strings.TrimSpace(input[:i]), strings.TrimSpace(input[i:])
+	if !u.matches(suffix) {
+		return "", fmt.Errorf("invalid input: give your answer in %s", u.Plural)
+	}
+	return number, nil
+}
+
+// comparisonSymbols maps the accepted comparison inputs to their encoding
+var comparisonSymbols = map[string]int{
+	"<":            -1,
+	"less":         -1,
+	"less than":    -1,
+	"=":            0,
+	"==":           0,
+	"equal":        0,
+	"equals":       0,
+	">":            1,
+	"greater":      1,
+	"greater than": 1,
+}
+
+// parseComparison parses <, = or > (or the words for them)
+func parseComparison(p Problem, input string) (int, error) {
+	if answer, ok := comparisonSymbols[strings.ToLower(input)]; ok {
+		return answer, nil
+	}
+	return 0, fmt.Errorf("invalid input: enter <, > or =")
+}
+
+// formatComparison returns the symbol for an encoded comparison
+func formatComparison(p Problem, answer int) string {
+	switch {
+	case answer < 0:
+		return "<"
+	case answer > 0:
+		return ">"
+	default:
+		return "="
+	}
+}
+
+// normalizeSign replaces a leading en-dash or Unicode minus sign with an
+// ASCII hyphen so negative answers can be typed either way
+func normalizeSign(input string) string {
+	input = strings.TrimSpace(input)
+	for _, dash := range []string{"–", "−"} {
+		if strings.HasPrefix(input, dash) {
+			return "-" + strings.TrimPrefix(input, dash)
+		}
+	}
+	return input
+}
@@ -0,0 +1,339 @@
+package problems
+
+// stepChoices lets the player choose step-by-step checking for long
+// multiplication and division
+var stepChoices = []string{"Just the answer", "Step by step"}
+
+// tablesSetting lets the player practice only some times tables in
+// multiplication and division
+var tablesSetting = Setting{
+	Key: "tables", Description: "times tables to practice", Default: 0, Min: 0, Max: 1<<(maxTable+1) - 2,
+	Tables: true,
+}
+
+// tableDifficulty rates multiplication and division by the largest factor
+func tableDifficulty(c Config) int {
+	switch {
+	case c["maxFactor"] <= 5:
+		return 1
+	case c["maxFactor"] <= 10:
+		return 2
+	case c["maxFactor"] <= 12:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// digitTiers step addition and subtraction up by the size of the numbers
+var digitTiers = []Tier{
+	{Name: "1-digit numbers", Config: Config{"maxDigits": 1}},
+	{Name: "2-digit numbers", Config: Config{"maxDigits": 2}},
+	{Name: "3-digit numbers", Config: Config{"maxDigits": 3}},
+	{Name: "4-digit numbers", Config: Config{"maxDigits": 4}},
+}
+
+// factorTiers step multiplication and division up through the tables
+var factorTiers = []Tier{
+	{Name: "Tables up to 5", Config: Config{"maxFactor": 5}},
+	{Name: "Tables up to 10", Config: Config{"maxFactor": 10}},
+	{Name: "Tables up to 12", Config: Config{"maxFactor": 12}},
+}
+
+// extraWordLibraries are merged into the bundled library whenever a word
+// problem generator is created from the registry
+var extraWordLibraries []*WordLibrary
+
+// AddWordLibrary adds names, items, units and templates to the word problems
+// created from the registry
+func AddWordLibrary(library *WordLibrary) {
+	extraWordLibraries = append(extraWordLibraries, library)
+}
+
+// The built-in problem types, in the order they appear in menus
+func init() {
+	Register(Definition{
+		Type: Addition,
+		Name: "Addition",
+		Settings: []Setting{
+			{Key: "maxDigits", Description: "maximum digits in each number", Default: 2, Min: 1, Max: 6},
+		},
+		New: func(c Config) (Generator, error) {
+			return NewAdditionGenerator(c["maxDigits"]), nil
+		},
+		Explain:    operatorExplainer("+"),
+		Difficulty: func(c Config) int { return c["maxDigits"] },
+		Tiers:      digitTiers,
+	})
+
+	Register(Definition{
+		Type: Subtraction,
+		Name: "Subtraction",
+		Settings: []Setting{
+			{Key: "maxDigits", Description: "maximum digits in each number", Default: 2, Min: 1, Max: 6},
+		},
+		New: func(c Config) (Generator, error) {
+			return NewSubtractionGenerator(c["maxDigits"]), nil
+		},
+		Explain:    operatorExplainer("-"),
+		Difficulty: func(c Config) int { return c["maxDigits"] },
+		Tiers:      digitTiers,
+	})
+
+	Register(Definition{
+		Type: Multiplication,
+		Name: "Multiplication",
+		Settings: []Setting{
+			{Key: "maxFactor", Description: "largest factor", Default: 12, Min: 1, Max: 20},
+			tablesSetting,
+		},
+		New: func(c Config) (Generator, error) {
+			return NewMultiplicationTablesGenerator(c["maxFactor"], MaskTables(c["tables"]))
+		},
+		Explain:    operatorExplainer("×"),
+		Difficulty: tableDifficulty,
+		Tiers:      factorTiers,
+	})
+
+	Register(Definition{
+		Type: Division,
+		Name: "Division",
+		Settings: []Setting{
+			{Key: "maxFactor", Description: "largest divisor and quotient", Default: 12, Min: 1, Max: 20},
+			tablesSetting,
+		},
+		New: func(c Config) (Generator, error) {
+			return NewDivisionTablesGenerator(c["maxFactor"], MaskTables(c["tables"]))
+		},
+		Explain:    operatorExplainer("÷"),
+		Difficulty: tableDifficulty,
+		Tiers:      factorTiers,
+	})
+
+	Register(Definition{
+		Type: Decimal,
+		Name: "Decimals",
+		Settings: []Setting{
+			{Key: "maxDigits", Description: "maximum whole-number digits", Default: 1, Min: 1, Max: 4},
+			{Key: "places", Description: "decimal places", Default: 2, Min: 1, Max: 3},
+		},
+		New: func(c Config) (Generator, error) {
+			return NewDecimalGenerator(c["maxDigits"], c["places"]), nil
+		},
+		Difficulty: func(c Config) int { return c["maxDigits"] + c["places"] - 1 },
+	})
+
+	Register(Definition{
+		Type: Money,
+		Name: "Money",
+		Settings: []Setting{
+			{Key: "maxDigits", Description: "maximum digits in the dollar amounts", Default: 1, Min: 1, Max: 4},
+		},
+		New: func(c Config) (Generator, error) {
+			return NewMoneyGenerator(c["maxDigits"]), nil
+		},
+		Difficulty: func(c Config) int { return c["maxDigits"] + 1 },
+	})
+
+	Register(Definition{
+		Type: Integers,
+		Name: "Integers",
+		Settings: []Setting{
+			{Key: "maxDigits", Description: "maximum digits for addition and subtraction", Default: 2, Min: 1, Max: 4},
+			{Key: "maxFactor", Description: "largest factor for multiplication and division", Default: 12, Min: 1, Max: 20},
+		},
+		New: func(c Config) (Generator, error) {
+			return NewIntegersGenerator(c["maxDigits"], c["maxFactor"]), nil
+		},
+		Difficulty: func(c Config) int { return c["maxDigits"] + 1 },
+	})
+
+	Register(Definition{
+		Type: WordProblem,
+		Name: "Word Problems",
+		Settings: []Setting{
+			{Key: "grade", Description: "highest grade of templates to use", Default: 3, Min: 1, Max: 6},
+		},
+		New: func(c Config) (Generator, error) {
+			library, err := DefaultWordLibrary()
+			if err != nil {
+				return nil, err
+			}
+			for _, extra := range extraWordLibraries {
+				library.Merge(extra)
+			}
+			return NewWordProblemGenerator(library, c["grade"])
+		},
+		Difficulty: func(c Config) int { return c["grade"] },
+	})
+
+	Register(Definition{
+		Type: PlaceValue,
+		Name: "Place Value",
+		Settings: []Setting{
+			{Key: "min", Description: "smallest number", Default: 10, Min: 1, Max: 999999},
+			{Key: "max", Description: "largest number", Default: 9999, Min: 1, Max: 999999},
+		},
+		New: func(c Config) (Generator, error) {
+			return NewPlaceValueGenerator(c["min"], c["max"]), nil
+		},
+		Difficulty: func(c Config) int { return len(digits(c["max"], 1)) - 2 },
+	})
+
+	Register(Definition{
+		Type: Rounding,
+		Name: "Rounding",
+		Settings: []Setting{
+			{Key: "min", Description: "smallest number", Default: 10, Min: 1, Max: 999999},
+			{Key: "max", Description: "largest number", Default: 999, Min: 1, Max: 999999},
+		},
+		New: func(c Config) (Generator, error) {
+			return NewRoundingGenerator(c["min"], c["max"]), nil
+		},
+		Explain:    explainRounding,
+		Difficulty: func(c Config) int { return len(digits(c["max"], 1)) - 1 },
+	})
+
+	Register(Definition{
+		Type: Estimation,
+		Name: "Estimation",
+		Settings: []Setting{
+			{Key: "min", Description: "smallest number", Default: 10, Min: 1, Max: 99999},
+			{Key: "max", Description: "largest number", Default: 99, Min: 1, Max: 99999},
+		},
+		New: func(c Config) (Generator, error) {
+			return NewEstimationGenerator(c["min"], c["max"]), nil
+		},
+		Difficulty: func(c Config) int { return len(digits(c["max"], 1)) },
+	})
+
+	Register(Definition{
+		Type: Comparison,
+		Name: "Comparison",
+		Settings: []Setting{
+			{Key: "maxDigits", Description: "maximum digits in each number", Default: 2, Min: 1, Max: 4},
+		},
+		New: func(c Config) (Generator, error) {
+			return NewComparisonGenerator(c["maxDigits"]), nil
+		},
+		Difficulty: func(c Config) int { return c["maxDigits"] },
+	})
+
+	Register(Definition{
+		Type: Time,
+		Name: "Telling Time",
+		Settings: []Setting{
+			{Key: "minuteStep", Description: "minutes between clock times", Default: 5, Min: 1, Max: 30},
+		},
+		New: func(c Config) (Generator, error) {
+			return NewTimeGenerator(c["minuteStep"]), nil
+		},
+		Difficulty: func(c Config) int { return 3 - min(c["minuteStep"]/5, 2) },
+	})
+
+	Register(Definition{
+		Type: Measurement,
+		Name: "Measurement",
+		Settings: []Setting{
+			{Key: "maxQuantity", Description: "largest quantity of the bigger unit", Default: 10, Min: 1, Max: 100},
+			{Key: "system", Description: "units to use", Default: 0, Min: 0, Max: 2,
+				Choices: []string{"Metric and US customary", "Metric", "US customary"}},
+		},
+		New: func(c Config) (Generator, error) {
+			switch c["system"] {
+			case 1:
+				return NewMeasurementGenerator(c["maxQuantity"], Metric), nil
+			case 2:
+				return NewMeasurementGenerator(c["maxQuantity"], Customary), nil
+			default:
+				return NewMeasurementGenerator(c["maxQuantity"]), nil
+			}
+		},
+	})
+
+	Register(Definition{
+		Type: Geometry,
+		Name: "Area and Perimeter",
+		Settings: []Setting{
+			{Key: "maxSide", Description: "longest side length", Default: 12, Min: 4, Max: 50},
+		},
+		New: func(c Config) (Generator, error) {
+			return NewGeometryGenerator(c["maxSide"]), nil
+		},
+		Difficulty: func(c Config) int { return 1 + c["maxSide"]/20 },
+	})
+
+	Register(Definition{
+		Type: Sequence,
+		Name: "Number Patterns",
+		Settings: []Setting{
+			{Key: "length", Description: "terms in each sequence", Default: 5, Min: 4, Max: 10},
+		},
+		New: func(c Config) (Generator, error) {
+			return NewSequenceGenerator(c["length"]), nil
+		},
+		Difficulty: func(c Config) int { return 1 + (c["length"]-4)/2 },
+	})
+
+	Register(Definition{
+		Type: LongMultiplication,
+		Name: "Long Multiplication",
+		Settings: []Setting{
+			{Key: "topDigits", Description: "digits in the top number", Default: 3, Min: 2, Max: 5},
+			{Key: "bottomDigits", Description: "digits in the bottom number", Default: 2, Min: 1, Max: 3},
+			{Key: "steps", Description: "check each partial product", Default: 0, Min: 0, Max: 1, Choices: stepChoices},
+		},
+		New: func(c Config) (Generator, error) {
+			return NewLongMultiplicationGenerator(c["topDigits"], c["bottomDigits"], c["steps"] == 1), nil
+		},
+		Explain:    explainWorking("×", multiplicationSteps),
+		Difficulty: func(c Config) int { return c["topDigits"] + c["bottomDigits"] - 1 },
+		Tiers: []Tier{
+			{Name: "3-digit × 1-digit", Config: Config{"topDigits": 3, "bottomDigits": 1}},
+			{Name: "3-digit × 2-digit", Config: Config{"topDigits": 3, "bottomDigits": 2}},
+			{Name: "4-digit × 3-digit", Config: Config{"topDigits": 4, "bottomDigits": 3}},
+		},
+	})
+
+	Register(Definition{
+		Type: LongDivision,
+		Name: "Long Division",
+		Settings: []Setting{
+			{Key: "dividendDigits", Description: "digits in the dividend", Default: 4, Min: 2, Max: 6},
+			{Key: "maxDivisor", Description: "largest divisor", Default: 9, Min: 2, Max: 99},
+			{Key: "steps", Description: "check each divide and subtract step", Default: 0, Min: 0, Max: 1, Choices: stepChoices},
+		},
+		New: func(c Config) (Generator, error) {
+			return NewLongDivisionGenerator(c["dividendDigits"], c["maxDivisor"], c["steps"] == 1), nil
+		},
+		Explain:    explainWorking("÷", divisionSteps),
+		Difficulty: func(c Config) int { return c["dividendDigits"] + len(digits(c["maxDivisor"], 1)) - 2 },
+	})
+
+	Register(Definition{
+		Type: Exponent,
+		Name: "Exponents and Square Roots",
+		Settings: []Setting{
+			{Key: "maxBase", Description: "largest base and square root", Default: 12, Min: 1, Max: 30},
+			{Key: "maxExponent", Description: "largest power of ten", Default: 6, Min: 1, Max: 9},
+		},
+		New: func(c Config) (Generator, error) {
+			return NewExponentGenerator(c["maxBase"], c["maxExponent"]), nil
+		},
+		Explain:    explainExponent,
+		Difficulty: func(c Config) int { return 2 + c["maxBase"]/13 },
+	})
+
+	Register(Definition{
+		Type: Percent,
+		Name: "Percentages and Ratios",
+		Settings: []Setting{
+			{Key: "difficulty", Description: "difficulty level", Default: 1, Min: 1, Max: 3,
+				Choices: []string{"Easy", "Medium", "Hard"}},
+		},
+		New: func(c Config) (Generator, error) {
+			return NewPercentGenerator(c["difficulty"]), nil
+		},
+		Difficulty: func(c Config) int { return c["difficulty"] + 1 },
+	})
+}
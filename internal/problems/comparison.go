@@ -0,0 +1,80 @@
+package problems
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// ComparisonGenerator generates problems like "34 + 12 __ 50" where the
+// answer is <, > or =
+type ComparisonGenerator struct {
+	maxDigits int
+	random    *rand.Rand
+}
+
+// NewComparisonGenerator creates a new comparison problem generator using
+// numbers with up to maxDigits digits
+func NewComparisonGenerator(maxDigits int) *ComparisonGenerator {
+	return &ComparisonGenerator{
+		maxDigits: maxDigits,
+		random:    rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Generate creates a new comparison problem
+func (g *ComparisonGenerator) Generate() Problem {
+	maxNum := pow10(g.maxDigits) - 1
+	num1 := g.random.Intn(maxNum) + 1
+	num2 := g.random.Intn(maxNum) + 1
+
+	operator := "+"
+	value := num1 + num2
+	if g.random.Intn(2) == 0 {
+		if num2 > num1 {
+			num1, num2 = num2, num1
+		}
+		operator = "-"
+		value = num1 - num2
+	}
+
+	// Compare against a nearby number, and make "=" come up about a third
+	// of the time so it isn't a rare surprise
+	target := value
+	if g.random.Intn(3) != 0 {
+		for target == value || target < 0 {
+			target = value + g.random.Intn(21) - 10
+		}
+	}
+
+	return Problem{
+		Question:  fmt.Sprintf("%d %s %d __ %d", num1, operator, num2, target),
+		Answer:    compare(value, target),
+		Type:      Comparison,
+		Kind:      ComparisonAnswer,
+		Operands:  []int{num1, num2, target},
+		Statement: true,
+	}
+}
+
+// compare returns -1, 0 or 1 as a is less than, equal to or greater than b
+func compare(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Type returns the type of problems this generator creates
+func (g *ComparisonGenerator) Type() ProblemType {
+	return Comparison
+}
+
+// Name returns a human-readable name for this problem type
+func (g *ComparisonGenerator) Name() string {
+	return "Comparison"
+}
@@ -0,0 +1,431 @@
+package problems
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// maxCustomAttempts bounds how many random operand pairs are tried before
+// a custom definition's constraints are considered impossible to meet
+const maxCustomAttempts = 10000
+
+// maxCustomPairs is the most operand pairs listed when a custom generator
+// is created. Definitions with more pairs than this draw them at random.
+const maxCustomPairs = 100000
+
+// CustomSpec is a declarative definition of a problem family, as written
+// by a teacher in a JSON file
+type CustomSpec struct {
+	Type     ProblemType    `json:"type"`
+	Name     string         `json:"name"`
+	Operator string         `json:"operator"`
+	Operands []OperandRange `json:"operands"`
+
+	// Constraints are conditions such as "answer <= 100", "a > b" or
+	// "divisible" that every problem must meet
+	Constraints []string `json:"constraints"`
+
+	// Question is an optional template such as "What is {{.A}} more than
+	// {{.B}}?". Without one, problems are shown as "A + B = ?".
+	Question string `json:"question"`
+}
+
+// OperandRange is the range an operand is drawn from. If Step is set,
+// only multiples of it are used.
+type OperandRange struct {
+	Min  int `json:"min"`
+	Max  int `json:"max"`
+	Step int `json:"step"`
+}
+
+// customOperators maps the accepted operator spellings to how they are
+// shown and how they are computed
+var customOperators = map[string]struct {
+	symbol string
+	apply  func(a, b int) int
+}{
+	"+": {"+", func(a, b int) int { return a + b }},
+	"-": {"-", func(a, b int) int { return a - b }},
+	"×": {"×", func(a, b int) int { return a * b }},
+	"*": {"×", func(a, b int) int { return a * b }},
+	"x": {"×", func(a, b int) int { return a * b }},
+	"÷": {"÷", func(a, b int) int { return a / b }},
+	"/": {"÷", func(a, b int) int { return a / b }},
+}
+
+// constraint is a parsed condition on a problem's operands and answer
+type constraint func(a, b, answer int) bool
+
+// customPrefix starts the problem type of every custom definition, so a
+// definition can't share a history file with a built-in type or overwrite
+// another file in the data directory, such as config.json
+const customPrefix = "custom-"
+
+// customType returns the problem type of a custom definition
+func customType(spec CustomSpec) ProblemType {
+	return customPrefix + spec.Type
+}
+
+// CustomGenerator generates problems from a CustomSpec
+type CustomGenerator struct {
+	spec        CustomSpec
+	symbol      string
+	apply       func(a, b int) int
+	constraints []constraint
+	question    *template.Template
+	random      *rand.Rand
+
+	// pairs lists every pair of operands that meets the constraints, if
+	// there are few enough candidates to check them all
+	pairs [][2]int
+
+	// found is a pair that meets the constraints, asked if random drawing
+	// fails to find one
+	found [2]int
+}
+
+// NewCustomGenerator creates a generator from a declarative spec, checking
+// that the spec is complete and that its constraints can be met
+func NewCustomGenerator(spec CustomSpec) (*CustomGenerator, error) {
+	if spec.Type == "" || spec.Name == "" {
+		return nil, fmt.Errorf("custom problem needs a type and a name")
+	}
+
+	// The type names the history file, after customPrefix, so keep it to
+	// safe characters
+	for _, r := range spec.Type {
+		if !(r >= 'a' && r <= 'z') && !(r >= '0' && r <= '9') && r != '-' {
+			return nil, fmt.Errorf("type %q may only use lowercase letters, digits and dashes", spec.Type)
+		}
+	}
+
+	operator, ok := customOperators[spec.Operator]
+	if !ok {
+		return nil, fmt.Errorf("%s: unknown operator %q", spec.Type, spec.Operator)
+	}
+
+	if len(spec.Operands) != 2 {
+		return nil, fmt.Errorf("%s: expected 2 operand ranges, got %d", spec.Type, len(spec.Operands))
+	}
+	for _, r := range spec.Operands {
+		if r.Max < r.Min || r.Step < 0 {
+			return nil, fmt.Errorf("%s: invalid operand range %d to %d", spec.Type, r.Min, r.Max)
+		}
+		if low, high := bounds(r); low > high {
+			return nil, fmt.Errorf("%s: no multiples of %d between %d and %d", spec.Type, r.Step, r.Min, r.Max)
+		}
+	}
+
+	g := &CustomGenerator{
+		spec:   spec,
+		symbol: operator.symbol,
+		apply:  operator.apply,
+		random: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+
+	// Division must always come out even
+	if operator.symbol == "÷" {
+		g.constraints = append(g.constraints, divisible)
+	}
+	for _, text := range spec.Constraints {
+		c, err := parseConstraint(text)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", spec.Type, err)
+		}
+		g.constraints = append(g.constraints, c)
+	}
+
+	// List the valid pairs if that's cheap enough, so every problem is
+	// drawn from them; otherwise make sure at least one can be found.
+	// Dividing rather than multiplying the counts keeps huge ranges from
+	// overflowing.
+	if candidates(spec.Operands[0]) <= maxCustomPairs/candidates(spec.Operands[1]) {
+		g.pairs = g.validPairs()
+		if len(g.pairs) == 0 {
+			return nil, fmt.Errorf("%s: no problems meet the constraints", spec.Type)
+		}
+		g.found = g.pairs[0]
+	} else {
+		a, b, ok := g.operands()
+		if !ok {
+			return nil, fmt.Errorf("%s: no problems meet the constraints", spec.Type)
+		}
+		g.found = [2]int{a, b}
+	}
+
+	// Run the question once, since unknown fields only fail when it runs
+	if spec.Question != "" {
+		question, err := template.New(string(spec.Type)).Option("missingkey=error").Parse(spec.Question)
+		if err != nil {
+			return nil, fmt.Errorf("%s: failed to parse question: %w", spec.Type, err)
+		}
+		if err := question.Execute(io.Discard, questionData{g.found[0], g.found[1]}); err != nil {
+			return nil, fmt.Errorf("%s: failed to run question: %w", spec.Type, err)
+		}
+		g.question = question
+	}
+
+	return g, nil
+}
+
+// questionData is the data available to a custom question template
+type questionData struct {
+	A, B int
+}
+
+// bounds returns the first and last numbers of an operand range, or of the
+// multiples of Step in it counted in steps, without overflowing
+func bounds(r OperandRange) (low, high int) {
+	if r.Step <= 1 {
+		return r.Min, r.Max
+	}
+	low = r.Min / r.Step
+	if r.Min%r.Step > 0 {
+		low++
+	}
+	return low, r.Max / r.Step
+}
+
+// candidates returns how many numbers an operand range holds, counting no
+// further than maxCustomPairs + 1 so that huge ranges can't overflow
+func candidates(r OperandRange) int {
+	low, high := bounds(r)
+	return int(min(uint64(high)-uint64(low), maxCustomPairs)) + 1
+}
+
+// values returns every number in an operand range, the same numbers draw
+// picks from
+func values(r OperandRange) []int {
+	low, high := bounds(r)
+	step := max(r.Step, 1)
+
+	numbers := make([]int, 0, high-low+1)
+	for k := low; k <= high; k++ {
+		numbers = append(numbers, k*step)
+	}
+	return numbers
+}
+
+// validPairs returns every pair of operands that meets the constraints
+func (g *CustomGenerator) validPairs() [][2]int {
+	var pairs [][2]int
+	for _, a := range values(g.spec.Operands[0]) {
+		for _, b := range values(g.spec.Operands[1]) {
+			if g.symbol == "÷" && b == 0 {
+				continue
+			}
+			if g.meets(a, b, g.apply(a, b)) {
+				pairs = append(pairs, [2]int{a, b})
+			}
+		}
+	}
+	return pairs
+}
+
+// pick returns the operands for the next problem
+func (g *CustomGenerator) pick() (int, int) {
+	if len(g.pairs) > 0 {
+		pair := g.pairs[g.random.Intn(len(g.pairs))]
+		return pair[0], pair[1]
+	}
+	if a, b, ok := g.operands(); ok {
+		return a, b
+	}
+	return g.found[0], g.found[1]
+}
+
+// operands draws operands until they meet every constraint
+func (g *CustomGenerator) operands() (int, int, bool) {
+	for i := 0; i < maxCustomAttempts; i++ {
+		a := g.draw(g.spec.Operands[0])
+		b := g.draw(g.spec.Operands[1])
+		if g.symbol == "÷" && b == 0 {
+			continue
+		}
+
+		if g.meets(a, b, g.apply(a, b)) {
+			return a, b, true
+		}
+	}
+	return 0, 0, false
+}
+
+// meets reports whether a problem satisfies every constraint
+func (g *CustomGenerator) meets(a, b, answer int) bool {
+	for _, c := range g.constraints {
+		if !c(a, b, answer) {
+			return false
+		}
+	}
+	return true
+}
+
+// draw picks a random number from an operand range
+func (g *CustomGenerator) draw(r OperandRange) int {
+	if r.Step <= 1 {
+		return randomBetween(g.random, r.Min, r.Max)
+	}
+
+	// Pick among the multiples of Step within the range
+	first, last := bounds(r)
+	return randomBetween(g.random, first, last) * r.Step
+}
+
+// Generate creates a new problem from the spec
+func (g *CustomGenerator) Generate() Problem {
+	a, b := g.pick()
+	answer := g.apply(a, b)
+
+	problem := Problem{
+		Question: fmt.Sprintf("%d %s %d", a, g.symbol, b),
+		Answer:   answer,
+		Type:     customType(g.spec),
+		Operands: []int{a, b},
+	}
+
+	if g.question != nil {
+		// The question ran when the generator was created, so this shouldn't
+		// fail; if it does, the plain problem can still be asked
+		var question strings.Builder
+		if err := g.question.Execute(&question, questionData{a, b}); err == nil {
+			problem.Question = question.String()
+			problem.Statement = true
+		}
+	}
+
+	return problem
+}
+
+// Type returns the type of problems this generator creates
+func (g *CustomGenerator) Type() ProblemType {
+	return customType(g.spec)
+}
+
+// Name returns a human-readable name for this problem type
+func (g *CustomGenerator) Name() string {
+	return g.spec.Name
+}
+
+// divisible requires the first operand to be a multiple of the second
+func divisible(a, b, answer int) bool {
+	return b != 0 && a%b == 0
+}
+
+// comparisons maps the operators allowed in constraints to their checks
+var comparisons = map[string]func(x, y int) bool{
+	"<=": func(x, y int) bool { return x <= y },
+	"≤":  func(x, y int) bool { return x <= y },
+	">=": func(x, y int) bool { return x >= y },
+	"≥":  func(x, y int) bool { return x >= y },
+	"<":  func(x, y int) bool { return x < y },
+	">":  func(x, y int) bool { return x > y },
+	"=":  func(x, y int) bool { return x == y },
+	"==": func(x, y int) bool { return x == y },
+	"!=": func(x, y int) bool { return x != y },
+	"≠":  func(x, y int) bool { return x != y },
+}
+
+// parseConstraint parses "divisible" or a comparison such as
+// "answer <= 100" or "a > b", where each side is a, b, answer or a number
+func parseConstraint(text string) (constraint, error) {
+	fields := strings.Fields(text)
+	if len(fields) == 1 && fields[0] == "divisible" {
+		return divisible, nil
+	}
+	if len(fields) != 3 {
+		return nil, fmt.Errorf("invalid constraint %q", text)
+	}
+
+	compare, ok := comparisons[fields[1]]
+	if !ok {
+		return nil, fmt.Errorf("invalid comparison %q in constraint %q", fields[1], text)
+	}
+
+	left, err := constraintTerm(fields[0])
+	if err != nil {
+		return nil, fmt.Errorf("%w in constraint %q", err, text)
+	}
+	right, err := constraintTerm(fields[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w in constraint %q", err, text)
+	}
+
+	return func(a, b, answer int) bool {
+		return compare(left(a, b, answer), right(a, b, answer))
+	}, nil
+}
+
+// constraintTerm parses one side of a constraint comparison
+func constraintTerm(term string) (func(a, b, answer int) int, error) {
+	switch term {
+	case "a":
+		return func(a, b, answer int) int { return a }, nil
+	case "b":
+		return func(a, b, answer int) int { return b }, nil
+	case "answer":
+		return func(a, b, answer int) int { return answer }, nil
+	}
+
+	n, err := strconv.Atoi(term)
+	if err != nil {
+		return nil, fmt.Errorf("unknown term %q", term)
+	}
+	return func(a, b, answer int) int { return n }, nil
+}
+
+// LoadCustomSpec reads a declarative problem definition from a JSON file
+func LoadCustomSpec(path string) (CustomSpec, error) {
+	var spec CustomSpec
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return spec, fmt.Errorf("failed to read problem definition: %w", err)
+	}
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return spec, fmt.Errorf("failed to unmarshal %s: %w", filepath.Base(path), err)
+	}
+	return spec, nil
+}
+
+// RegisterCustomDir registers a problem type for every .json definition in
+// dir. A missing directory is not an error.
+func RegisterCustomDir(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return fmt.Errorf("failed to list problem definitions: %w", err)
+	}
+
+	for _, path := range paths {
+		spec, err := LoadCustomSpec(path)
+		if err != nil {
+			return err
+		}
+
+		// Check the spec now so mistakes show up at startup, not mid-game
+		if _, err := NewCustomGenerator(spec); err != nil {
+			return fmt.Errorf("%s: %w", filepath.Base(path), err)
+		}
+		if _, ok := Lookup(customType(spec)); ok {
+			return fmt.Errorf("%s: problem type %q already exists", filepath.Base(path), spec.Type)
+		}
+
+		Register(Definition{
+			Type: customType(spec),
+			Name: spec.Name,
+			New: func(c Config) (Generator, error) {
+				return NewCustomGenerator(spec)
+			},
+			Explain: operatorExplainer(customOperators[spec.Operator].symbol),
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package problems
+
+import (
+	"fmt"
+	"time"
+)
+
+// DailyName is the display name of the daily challenge
+const DailyName = "Daily Challenge"
+
+// DailyDifficulty is the difficulty the daily challenge is scored at
+const DailyDifficulty = 2
+
+// dailyProblemsPerType is how many problems of each operation the daily
+// challenge asks
+const dailyProblemsPerType = 5
+
+// splitMix64 is a small random number generator whose output depends only
+// on its seed. Unlike math/rand it is written out here, so a seed gives the
+// same numbers on every platform and Go version.
+type splitMix64 struct {
+	state uint64
+}
+
+// next returns the next 64 random bits
+func (s *splitMix64) next() uint64 {
+	s.state += 0x9e3779b97f4a7c15
+	z := s.state
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// between returns a random number from min to max inclusive
+func (s *splitMix64) between(min, max int) int {
+	return min + int(s.next()%uint64(max-min+1))
+}
+
+// DailyGenerator serves the same mixed set of problems to everyone who
+// plays on a given calendar day
+type DailyGenerator struct {
+	problems []Problem
+	next     int
+}
+
+// NewDailyGenerator creates the daily challenge for the calendar day of
+// date, in date's time zone
+func NewDailyGenerator(date time.Time) *DailyGenerator {
+	year, month, day := date.Date()
+	random := &splitMix64{state: uint64(year*10000 + int(month)*100 + day)}
+
+	var set []Problem
+	for i := 0; i < dailyProblemsPerType; i++ {
+		a, b := random.between(10, 99), random.between(10, 99)
+		set = append(set, Problem{
+			Question: fmt.Sprintf("%d + %d", a, b),
+			Answer:   a + b,
+			Type:     Addition,
+			Operands: []int{a, b},
+		})
+	}
+	for i := 0; i < dailyProblemsPerType; i++ {
+		a := random.between(20, 99)
+		b := random.between(10, a-1)
+		set = append(set, Problem{
+			Question: fmt.Sprintf("%d - %d", a, b),
+			Answer:   a - b,
+			Type:     Subtraction,
+			Operands: []int{a, b},
+		})
+	}
+	for i := 0; i < dailyProblemsPerType; i++ {
+		set = append(set, multiplicationFact(random.between(2, 12), random.between(2, 12)))
+	}
+	for i := 0; i < dailyProblemsPerType; i++ {
+		set = append(set, divisionFact(random.between(2, 12), random.between(2, 12)))
+	}
+
+	// Mix the operations with a Fisher-Yates shuffle
+	for i := len(set) - 1; i > 0; i-- {
+		j := random.between(0, i)
+		set[i], set[j] = set[j], set[i]
+	}
+
+	return &DailyGenerator{problems: set}
+}
+
+// Generate returns the next problem of the challenge, starting over once
+// every problem has been served
+func (g *DailyGenerator) Generate() Problem {
+	if g.next == len(g.problems) {
+		g.next = 0
+	}
+	problem := g.problems[g.next]
+	g.next++
+	return problem
+}
+
+// Len returns the number of problems in the challenge
+func (g *DailyGenerator) Len() int {
+	return len(g.problems)
+}
+
+// Type returns the type of problems this generator creates
+func (g *DailyGenerator) Type() ProblemType {
+	return Daily
+}
+
+// Name returns a human-readable name for this problem type
+func (g *DailyGenerator) Name() string {
+	return DailyName
+}
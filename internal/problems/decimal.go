@@ -0,0 +1,153 @@
+package problems
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// DecimalGenerator generates decimal addition and subtraction problems.
+// All values are kept as fixed-point integers scaled by 10^places so that
+// answers compare exactly.
+type DecimalGenerator struct {
+	maxDigits int
+	places    int
+	money     bool
+	random    *rand.Rand
+}
+
+// NewDecimalGenerator creates a new decimal problem generator with up to
+// maxDigits whole digits and the given number of decimal places
+func NewDecimalGenerator(maxDigits, places int) *DecimalGenerator {
+	return &DecimalGenerator{
+		maxDigits: maxDigits,
+		places:    places,
+		random:    rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// NewMoneyGenerator creates a new money problem generator with amounts of
+// up to maxDigits whole dollars
+func NewMoneyGenerator(maxDigits int) *DecimalGenerator {
+	return &DecimalGenerator{
+		maxDigits: maxDigits,
+		places:    2,
+		money:     true,
+		random:    rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Generate creates a new decimal addition or subtraction problem
+func (g *DecimalGenerator) Generate() Problem {
+	// Generate both amounts in fixed-point units
+	maxValue := pow10(g.maxDigits+g.places) - 1
+	num1 := g.random.Intn(maxValue) + 1
+	num2 := g.random.Intn(maxValue) + 1
+
+	operator := "+"
+	answer := num1 + num2
+	if g.random.Intn(2) == 0 {
+		// Keep subtraction results positive
+		if num2 > num1 {
+			num1, num2 = num2, num1
+		}
+		operator = "-"
+		answer = num1 - num2
+	}
+
+	return Problem{
+		Question: fmt.Sprintf("%s %s %s", g.format(num1), operator, g.format(num2)),
+		Answer:   answer,
+		Type:     g.Type(),
+		Decimals: g.places,
+		Operands: []int{num1, num2},
+	}
+}
+
+// format renders a fixed-point value the way it appears in a question
+func (g *DecimalGenerator) format(value int) string {
+	if g.money {
+		return "$" + FormatDecimal(value, g.places)
+	}
+	return FormatDecimal(value, g.places)
+}
+
+// Type returns the type of problems this generator creates
+func (g *DecimalGenerator) Type() ProblemType {
+	if g.money {
+		return Money
+	}
+	return Decimal
+}
+
+// Name returns a human-readable name for this problem type
+func (g *DecimalGenerator) Name() string {
+	if g.money {
+		return "Money"
+	}
+	return "Decimals"
+}
+
+// FormatDecimal formats a fixed-point value with the given number of
+// decimal places, e.g. FormatDecimal(525, 2) returns "5.25"
+func FormatDecimal(value, places int) string {
+	if places <= 0 {
+		return fmt.Sprintf("%d", value)
+	}
+
+	sign := ""
+	if value < 0 {
+		sign = "-"
+		value = -value
+	}
+
+	scale := pow10(places)
+	return fmt.Sprintf("%s%d.%0*d", sign, value/scale, places, value%scale)
+}
+
+// ParseDecimal parses a decimal number such as "5.25", "$5.25", "5.250" or
+// "-5.25" into a fixed-point value with the given number of decimal places.
+// A negative amount of money can be written "-$5.25" or "$-5.25". Digits
+// beyond places are accepted only if they are zeros.
+func ParseDecimal(input string, places int) (int, error) {
+	s := normalizeSign(input)
+
+	// The dollar sign may come before or after the minus sign
+	dollar := strings.HasPrefix(s, "$")
+	if dollar {
+		s = normalizeSign(s[1:])
+	}
+	sign := 1
+	if strings.HasPrefix(s, "-") {
+		sign = -1
+		s = s[1:]
+	}
+	if !dollar {
+		s = strings.TrimPrefix(s, "$")
+	}
+
+	whole, fraction, hasPoint := strings.Cut(s, ".")
+	if whole == "" && (!hasPoint || fraction == "") {
+		return 0, fmt.Errorf("invalid input: %q is not a number", input)
+	}
+
+	// Extra fraction digits are fine as long as they don't change the value
+	if len(fraction) > places {
+		if strings.Trim(fraction[places:], "0") != "" {
+			return 0, fmt.Errorf("invalid input: use at most %d decimal places", places)
+		}
+		fraction = fraction[:places]
+	}
+	fraction += strings.Repeat("0", places-len(fraction))
+
+	value := 0
+	for _, r := range whole + fraction {
+		if r < '0' || r > '9' {
+			return 0, fmt.Errorf("invalid input: %q is not a number", input)
+		}
+		value = value*10 + int(r-'0')
+	}
+
+	return sign * value, nil
+}
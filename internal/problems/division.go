@@ -3,12 +3,14 @@ package problems
 import (
 	"fmt"
 	"math/rand"
+	"slices"
 	"time"
 )
 
 // DivisionGenerator generates division problems
 type DivisionGenerator struct {
 	maxFactor int
+	tables    []int
 	random    *rand.Rand
 }
 
@@ -20,6 +22,19 @@ func NewDivisionGenerator(maxFactor int) *DivisionGenerator {
 	}
 }
 
+// NewDivisionTablesGenerator creates a division problem generator for the
+// facts of the given times tables only, so 56 ÷ 7 and 56 ÷ 8 both practice
+// the 7s. A nil list practices every table.
+func NewDivisionTablesGenerator(maxFactor int, tables []int) (*DivisionGenerator, error) {
+	if err := checkTables(tables, maxFactor); err != nil {
+		return nil, err
+	}
+
+	g := NewDivisionGenerator(maxFactor)
+	g.tables = slices.Clone(tables)
+	return g, nil
+}
+
 // Generate creates a new division problem
 func (g *DivisionGenerator) Generate() Problem {
 	// For division, we'll generate a multiplication problem first,
@@ -27,14 +42,64 @@ func (g *DivisionGenerator) Generate() Problem {
 	factor1 := g.random.Intn(g.maxFactor) + 1
 	factor2 := g.random.Intn(g.maxFactor) + 1
 
-	product := factor1 * factor2
+	// With tables selected, one factor comes from them and may be either
+	// the divisor or the answer
+	if len(g.tables) > 0 {
+		factor1 = g.tables[g.random.Intn(len(g.tables))]
+		if g.random.Intn(2) == 0 {
+			factor1, factor2 = factor2, factor1
+		}
+	}
 
 	// Create a division problem using the product and one of the factors
+	return divisionFact(factor1, factor2)
+}
+
+// divisionFact creates the problem (divisor × quotient) ÷ divisor
+func divisionFact(divisor, quotient int) Problem {
+	product := divisor * quotient
+
 	return Problem{
-		Question: fmt.Sprintf("%d ÷ %d", product, factor1),
-		Answer:   factor2,
+		Question: fmt.Sprintf("%d ÷ %d", product, divisor),
+		Answer:   quotient,
 		Type:     Division,
+		Operands: []int{product, divisor},
+	}
+}
+
+// Enumerate returns every division fact up to maxFactor, one row per table.
+// Rows of selected tables include facts with the table as the answer too.
+func (g *DivisionGenerator) Enumerate() [][]Problem {
+	rows := make([][]Problem, 0, g.maxFactor)
+	for _, table := range g.practiced() {
+		row := make([]Problem, 0, 2*g.maxFactor)
+		for factor := 1; factor <= g.maxFactor; factor++ {
+			row = append(row, divisionFact(table, factor))
+			if len(g.tables) > 0 && factor != table {
+				row = append(row, divisionFact(factor, table))
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// practiced returns the selected tables, or every table up to maxFactor
+func (g *DivisionGenerator) practiced() []int {
+	if len(g.tables) > 0 {
+		return g.tables
 	}
+
+	tables := make([]int, g.maxFactor)
+	for i := range tables {
+		tables[i] = i + 1
+	}
+	return tables
+}
+
+// Tables returns the times tables being practiced, or nil for all of them
+func (g *DivisionGenerator) Tables() []int {
+	return slices.Clone(g.tables)
 }
 
 // Type returns the type of problems this generator creates
@@ -0,0 +1,71 @@
+package problems
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// EstimationGenerator generates addition and subtraction estimation
+// problems. Any answer within half a rounding place of the estimate is
+// accepted.
+type EstimationGenerator struct {
+	min    int
+	max    int
+	random *rand.Rand
+}
+
+// NewEstimationGenerator creates a new estimation problem generator using
+// numbers from min to max, limited to 0 through 9,999,999
+func NewEstimationGenerator(min, max int) *EstimationGenerator {
+	min, max = placeRange(min, max)
+	return &EstimationGenerator{
+		min:    min,
+		max:    max,
+		random: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Generate creates a new estimation problem
+func (g *EstimationGenerator) Generate() Problem {
+	num1 := randomBetween(g.random, g.min, g.max)
+	num2 := randomBetween(g.random, g.min, g.max)
+
+	// Round to the leading place of the smaller number, but at least tens
+	power := 1
+	for pow10(power+1) <= min(num1, num2) {
+		power++
+	}
+	place := pow10(power)
+
+	operator := "+"
+	estimate := roundTo(num1, place) + roundTo(num2, place)
+	if g.random.Intn(2) == 0 {
+		if num2 > num1 {
+			num1, num2 = num2, num1
+		}
+		operator = "-"
+		estimate = roundTo(num1, place) - roundTo(num2, place)
+	}
+
+	return Problem{
+		Question: fmt.Sprintf("Estimate %s %s %s by rounding to the nearest %s.",
+			FormatThousands(num1), operator, FormatThousands(num2), placeUnit(power)),
+		Answer:    estimate,
+		Type:      Estimation,
+		Operands:  []int{num1, num2},
+		Statement: true,
+		Thousands: true,
+		Tolerance: place / 2,
+	}
+}
+
+// Type returns the type of problems this generator creates
+func (g *EstimationGenerator) Type() ProblemType {
+	return Estimation
+}
+
+// Name returns a human-readable name for this problem type
+func (g *EstimationGenerator) Name() string {
+	return "Estimation"
+}
@@ -0,0 +1,329 @@
+package problems
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Explanation is a worked solution for a problem
+type Explanation struct {
+	// Hint nudges towards the answer without giving it away
+	Hint string
+
+	// Steps walk through the solution, one line each
+	Steps []string
+}
+
+// Explain returns the worked solution for a problem, built from its
+// operands by the explainer registered for its type. Problems without one
+// are explained from their Steps, if they have any.
+func Explain(problem Problem) (Explanation, bool) {
+	var explanation Explanation
+	if def, ok := Lookup(problem.Type); ok && def.Explain != nil {
+		explanation = def.Explain(problem)
+	}
+	if len(explanation.Steps) == 0 && len(problem.Steps) > 0 {
+		explanation = explainSteps(problem)
+	}
+
+	return explanation, len(explanation.Steps) > 0
+}
+
+// explainSteps explains a problem by answering each of its steps in turn
+func explainSteps(problem Problem) Explanation {
+	explanation := Explanation{Hint: problem.Steps[0].Prompt}
+	for _, step := range problem.Steps {
+		if prompt, ok := strings.CutSuffix(step.Prompt, "= ?"); ok {
+			explanation.Steps = append(explanation.Steps, fmt.Sprintf("%s= %d", prompt, step.Answer))
+		} else {
+			explanation.Steps = append(explanation.Steps, fmt.Sprintf("%s %d", step.Prompt, step.Answer))
+		}
+	}
+	return explanation
+}
+
+// operatorExplainer returns the explainer for problems built from two
+// operands with the given operator symbol, or nil if there is none
+func operatorExplainer(symbol string) func(Problem) Explanation {
+	explain := map[string]func(a, b int) Explanation{
+		"+": explainAddition,
+		"-": explainSubtraction,
+		"×": explainMultiplication,
+		"÷": explainDivision,
+	}[symbol]
+	if explain == nil {
+		return nil
+	}
+
+	return func(problem Problem) Explanation {
+		if len(problem.Operands) != 2 || problem.Operands[0] < 0 || problem.Operands[1] < 0 {
+			return Explanation{}
+		}
+		return explain(problem.Operands[0], problem.Operands[1])
+	}
+}
+
+// digits returns the digits of n from the ones place up, with at least
+// count of them
+func digits(n, count int) []int {
+	var result []int
+	for n > 0 || len(result) < count {
+		result = append(result, n%10)
+		n /= 10
+	}
+	return result
+}
+
+// explainAddition makes ten for single digits and adds column by column,
+// carrying, for bigger numbers
+func explainAddition(a, b int) Explanation {
+	if a < 10 && b < 10 && a+b > 10 {
+		big, small := max(a, b), min(a, b)
+		need := 10 - big
+		return Explanation{
+			Hint: fmt.Sprintf("Make a ten first: %d + ? = 10.", big),
+			Steps: []string{
+				fmt.Sprintf("%d + %d = 10, so split %d into %d + %d.", big, need, small, need, small-need),
+				fmt.Sprintf("%d + %d = 10 + %d = %d.", a, b, small-need, a+b),
+			},
+		}
+	}
+
+	if a < 10 && b < 10 {
+		return Explanation{
+			Hint:  fmt.Sprintf("Start at %d and count on %d.", max(a, b), min(a, b)),
+			Steps: []string{fmt.Sprintf("Start at %d and count on %d: %d + %d = %d.", max(a, b), min(a, b), a, b, a+b)},
+		}
+	}
+
+	columns := max(len(digits(a, 1)), len(digits(b, 1)))
+	top, bottom := digits(a, columns), digits(b, columns)
+
+	explanation := Explanation{Hint: fmt.Sprintf("Start with the ones: %d + %d.", top[0], bottom[0])}
+	carry := 0
+	for place := range top {
+		sum := top[place] + bottom[place] + carry
+		line := fmt.Sprintf("Add the %s: %d + %d", placeNames[place], top[place], bottom[place])
+		if carry > 0 {
+			line += " + 1 carried"
+		}
+		line += fmt.Sprintf(" = %d", sum)
+		if sum >= 10 && place < len(top)-1 {
+			line += fmt.Sprintf(". Write %d and carry 1", sum%10)
+		}
+		explanation.Steps = append(explanation.Steps, line+".")
+		carry = sum / 10
+	}
+
+	explanation.Steps = append(explanation.Steps, fmt.Sprintf("So %d + %d = %d.", a, b, a+b))
+	return explanation
+}
+
+// explainSubtraction subtracts column by column, borrowing from the left
+func explainSubtraction(a, b int) Explanation {
+	if a < b {
+		return Explanation{}
+	}
+
+	top := digits(a, 1)
+	bottom := digits(b, len(top))
+	lent := make([]bool, len(top))
+
+	explanation := Explanation{Hint: fmt.Sprintf("Start with the ones: %d - %d. Do you need to borrow?", top[0], bottom[0])}
+	for place := range top {
+		digit := top[place]
+		line := fmt.Sprintf("Subtract the %s: ", placeNames[place])
+		if lent[place] {
+			line = fmt.Sprintf("Subtract the %s (now %d after lending): ", placeNames[place], digit)
+		}
+
+		if digit < bottom[place] {
+			// Borrow from the nearest place on the left that isn't zero,
+			// turning any zeros on the way into nines
+			from := place + 1
+			for top[from] == 0 {
+				from++
+			}
+			top[from]--
+			lent[from] = true
+			for between := from - 1; between > place; between-- {
+				top[between] = 9
+				lent[between] = true
+			}
+			digit += 10
+
+			line += fmt.Sprintf("%d is less than %d, so borrow from the %s to make %d. ", digit-10, bottom[place], placeNames[from], digit)
+		}
+
+		line += fmt.Sprintf("%d - %d = %d.", digit, bottom[place], digit-bottom[place])
+		explanation.Steps = append(explanation.Steps, line)
+	}
+
+	explanation.Steps = append(explanation.Steps, fmt.Sprintf("So %d - %d = %d.", a, b, a-b))
+	return explanation
+}
+
+// explainMultiplication breaks the larger factor into easier facts
+func explainMultiplication(a, b int) Explanation {
+	big, small := max(a, b), min(a, b)
+	product := a * b
+
+	switch {
+	case small == 0:
+		return Explanation{
+			Hint:  "What is any number times 0?",
+			Steps: []string{fmt.Sprintf("Any number times 0 is 0, so %d × %d = 0.", a, b)},
+		}
+	case small == 1:
+		return Explanation{
+			Hint:  "What is any number times 1?",
+			Steps: []string{fmt.Sprintf("Any number times 1 is itself, so %d × %d = %d.", a, b, product)},
+		}
+	case big == 10:
+		return Explanation{
+			Hint:  fmt.Sprintf("To multiply %d by 10, put a 0 on the end.", small),
+			Steps: []string{fmt.Sprintf("Put a 0 on the end of %d: %d × 10 = %d.", small, small, product)},
+		}
+	case big > 10:
+		rest := big - 10
+		return Explanation{
+			Hint: fmt.Sprintf("Split %d into 10 + %d.", big, rest),
+			Steps: []string{
+				fmt.Sprintf("Split %d into 10 + %d.", big, rest),
+				fmt.Sprintf("10 × %d = %d and %d × %d = %d.", small, 10*small, rest, small, rest*small),
+				fmt.Sprintf("%d + %d = %d, so %d × %d = %d.", 10*small, rest*small, product, a, b, product),
+			},
+		}
+	case big == 9:
+		return Explanation{
+			Hint: fmt.Sprintf("Work out %d × 10, then take away one %d.", small, small),
+			Steps: []string{
+				fmt.Sprintf("%d × 10 = %d.", small, 10*small),
+				fmt.Sprintf("Nine %ds is one %d less: %d - %d = %d.", small, small, 10*small, small, product),
+			},
+		}
+	case big%2 == 0 && big >= 4:
+		half := big / 2
+		return Explanation{
+			Hint: fmt.Sprintf("Think of %d as %d × 2. What is %d × %d?", big, half, small, half),
+			Steps: []string{
+				fmt.Sprintf("%d is %d × 2, so work out %d × %d and double it.", big, half, small, half),
+				fmt.Sprintf("%d × %d = %d.", small, half, small*half),
+				fmt.Sprintf("Double it: %d × 2 = %d, so %d × %d = %d.", small*half, product, a, b, product),
+			},
+		}
+	case big > 5:
+		rest := big - 5
+		return Explanation{
+			Hint: fmt.Sprintf("Split %d into 5 + %d.", big, rest),
+			Steps: []string{
+				fmt.Sprintf("Split %d into 5 + %d.", big, rest),
+				fmt.Sprintf("5 × %d = %d and %d × %d = %d.", small, 5*small, rest, small, rest*small),
+				fmt.Sprintf("%d + %d = %d, so %d × %d = %d.", 5*small, rest*small, product, a, b, product),
+			},
+		}
+	case big == 5:
+		return Explanation{
+			Hint: fmt.Sprintf("%d × 5 is half of %d × 10.", small, small),
+			Steps: []string{
+				fmt.Sprintf("%d × 10 = %d.", small, 10*small),
+				fmt.Sprintf("Half of %d is %d, so %d × %d = %d.", 10*small, product, a, b, product),
+			},
+		}
+	default:
+		// Both factors are 2 or 3, small enough to add up
+		sum := strings.TrimSuffix(strings.Repeat(fmt.Sprintf("%d + ", small), big), " + ")
+		return Explanation{
+			Hint:  fmt.Sprintf("Think of it as %d groups of %d.", big, small),
+			Steps: []string{fmt.Sprintf("%d groups of %d: %s = %d, so %d × %d = %d.", big, small, sum, product, a, b, product)},
+		}
+	}
+}
+
+// explainDivision relates a division to its multiplication fact
+func explainDivision(a, b int) Explanation {
+	if b == 0 || a%b != 0 {
+		return Explanation{}
+	}
+
+	quotient := a / b
+	return Explanation{
+		Hint: fmt.Sprintf("Think of the multiplication fact: %d × ? = %d.", b, a),
+		Steps: []string{
+			fmt.Sprintf("%d ÷ %d asks how many %ds make %d, or %d × ? = %d.", a, b, b, a, b, a),
+			fmt.Sprintf("%d × %d = %d, so %d ÷ %d = %d.", b, quotient, a, a, b, quotient),
+		},
+	}
+}
+
+// explainExponent writes powers out as repeated multiplication and relates
+// square roots to squares
+func explainExponent(problem Problem) Explanation {
+	switch len(problem.Operands) {
+	case 1:
+		square, root := problem.Operands[0], problem.Answer
+		return Explanation{
+			Hint: fmt.Sprintf("Which number times itself makes %d?", square),
+			Steps: []string{
+				fmt.Sprintf("√%d is the number that makes %d when multiplied by itself.", square, square),
+				fmt.Sprintf("%d × %d = %d, so √%d = %d.", root, root, square, square, root),
+			},
+		}
+	case 2:
+		base, exponent := problem.Operands[0], problem.Operands[1]
+		if base == 10 {
+			return Explanation{
+				Hint:  fmt.Sprintf("10%s is 1 followed by %d zeros.", Superscript(exponent), exponent),
+				Steps: []string{fmt.Sprintf("10%s is 1 followed by %d zeros: %s.", Superscript(exponent), exponent, FormatThousands(problem.Answer))},
+			}
+		}
+
+		factors := strings.TrimSuffix(strings.Repeat(fmt.Sprintf("%d × ", base), exponent), " × ")
+		return Explanation{
+			Hint:  fmt.Sprintf("%d%s means %s.", base, Superscript(exponent), factors),
+			Steps: []string{fmt.Sprintf("%d%s means %s = %d.", base, Superscript(exponent), factors, problem.Answer)},
+		}
+	}
+	return Explanation{}
+}
+
+// explainRounding looks at the digit to the right of the rounding place
+func explainRounding(problem Problem) Explanation {
+	if len(problem.Operands) != 2 {
+		return Explanation{}
+	}
+
+	number, place := problem.Operands[0], problem.Operands[1]
+	power := len(digits(place, 0)) - 1
+	if power < 1 || power >= len(placeNames) {
+		return Explanation{}
+	}
+	next := number / (place / 10) % 10
+
+	direction := "less than 5, so round down"
+	if next >= 5 {
+		direction = "5 or more, so round up"
+	}
+	return Explanation{
+		Hint: fmt.Sprintf("Look at the %s digit, just to the right of the %s place.", placeNames[power-1], placeNames[power]),
+		Steps: []string{
+			fmt.Sprintf("The %s digit of %s is %d.", placeNames[power-1], FormatThousands(number), next),
+			fmt.Sprintf("It is %s to %s.", direction, FormatThousands(problem.Answer)),
+		},
+	}
+}
+
+// explainWorking explains long multiplication and division through the
+// steps they are checked with, whether or not step-by-step mode is on
+func explainWorking(symbol string, steps func(a, b int) []Step) func(Problem) Explanation {
+	return func(problem Problem) Explanation {
+		if len(problem.Operands) != 2 {
+			return Explanation{}
+		}
+
+		a, b := problem.Operands[0], problem.Operands[1]
+		explanation := explainSteps(Problem{Steps: steps(a, b)})
+		explanation.Steps = append(explanation.Steps, fmt.Sprintf("So %d %s %d = %d.", a, symbol, b, problem.Answer))
+		return explanation
+	}
+}
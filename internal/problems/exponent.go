@@ -0,0 +1,142 @@
+package problems
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// superscriptDigits maps each digit to its Unicode superscript form
+var superscriptDigits = strings.NewReplacer(
+	"0", "⁰", "1", "¹", "2", "²", "3", "³", "4", "⁴",
+	"5", "⁵", "6", "⁶", "7", "⁷", "8", "⁸", "9", "⁹",
+)
+
+// ExponentGenerator generates problems with squares, cubes, powers of ten
+// and perfect square roots
+type ExponentGenerator struct {
+	maxBase     int
+	maxExponent int
+	random      *rand.Rand
+}
+
+// NewExponentGenerator creates a new exponent problem generator. Squares,
+// cubes and square roots use bases up to maxBase; powers of ten use
+// exponents up to maxExponent.
+func NewExponentGenerator(maxBase, maxExponent int) *ExponentGenerator {
+	return &ExponentGenerator{
+		maxBase:     maxBase,
+		maxExponent: maxExponent,
+		random:      rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Generate creates a new exponent or square root problem
+func (g *ExponentGenerator) Generate() Problem {
+	base := g.random.Intn(g.maxBase) + 1
+
+	switch g.random.Intn(4) {
+	case 0:
+		return g.power(base, 2)
+	case 1:
+		// Keep cubes small enough to work out by hand
+		return g.power(min(base, 10), 3)
+	case 2:
+		return g.power(10, g.random.Intn(g.maxExponent)+1)
+	default:
+		return Problem{
+			Question: fmt.Sprintf("√%d", base*base),
+			Answer:   base,
+			Type:     Exponent,
+			Operands: []int{base * base},
+		}
+	}
+}
+
+// power creates a problem asking for base raised to exponent
+func (g *ExponentGenerator) power(base, exponent int) Problem {
+	answer := 1
+	for i := 0; i < exponent; i++ {
+		answer *= base
+	}
+
+	return Problem{
+		Question: fmt.Sprintf("%d%s", base, Superscript(exponent)),
+		Answer:   answer,
+		Type:     Exponent,
+		Operands: []int{base, exponent},
+	}
+}
+
+// Superscript returns n written with Unicode superscript digits
+func Superscript(n int) string {
+	return superscriptDigits.Replace(strconv.Itoa(n))
+}
+
+// asciiSymbols maps math symbols to plain ASCII equivalents
+var asciiSymbols = map[rune]string{
+	'×': "x",
+	'÷': "/",
+	'–': "-",
+	'−': "-",
+}
+
+// ASCII rewrites math notation for terminals that can't show it: "7²"
+// becomes "7^2", "√49" becomes "sqrt(49)" and "×" becomes "x"
+func ASCII(s string) string {
+	var b strings.Builder
+	runes := []rune(s)
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case isSuperscript(r):
+			// Group a run of superscript digits behind a single caret
+			b.WriteString("^")
+			for ; i < len(runes) && isSuperscript(runes[i]); i++ {
+				b.WriteString(superscriptValue(runes[i]))
+			}
+			i--
+		case r == '√':
+			b.WriteString("sqrt(")
+			for i++; i < len(runes) && runes[i] >= '0' && runes[i] <= '9'; i++ {
+				b.WriteRune(runes[i])
+			}
+			b.WriteString(")")
+			i--
+		case asciiSymbols[r] != "":
+			b.WriteString(asciiSymbols[r])
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
+
+// isSuperscript reports whether r is a superscript digit
+func isSuperscript(r rune) bool {
+	return superscriptValue(r) != string(r)
+}
+
+// superscriptValue returns the plain digit for a superscript digit
+func superscriptValue(r rune) string {
+	for d := 0; d <= 9; d++ {
+		if Superscript(d) == string(r) {
+			return strconv.Itoa(d)
+		}
+	}
+	return string(r)
+}
+
+// Type returns the type of problems this generator creates
+func (g *ExponentGenerator) Type() ProblemType {
+	return Exponent
+}
+
+// Name returns a human-readable name for this problem type
+func (g *ExponentGenerator) Name() string {
+	return "Exponents and Square Roots"
+}
@@ -0,0 +1,161 @@
+package problems
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// lengthUnits are the units side lengths are measured in
+var lengthUnits = []string{"centimeter", "meter", "inch", "foot"}
+
+// squareUnits maps each length unit to its unit of area
+var squareUnits = map[string]string{
+	"centimeter": "square centimeter",
+	"meter":      "square meter",
+	"inch":       "square inch",
+	"foot":       "square foot",
+}
+
+// unitSymbols are the short labels used on drawings
+var unitSymbols = map[string]string{
+	"centimeter": "cm",
+	"meter":      "m",
+	"inch":       "in",
+	"foot":       "ft",
+}
+
+// GeometryGenerator generates area and perimeter problems for rectangles
+// and L-shaped figures, drawn in ASCII with labeled sides
+type GeometryGenerator struct {
+	maxSide int
+	random  *rand.Rand
+}
+
+// NewGeometryGenerator creates a new area and perimeter problem generator
+// with side lengths up to maxSide
+func NewGeometryGenerator(maxSide int) *GeometryGenerator {
+	return &GeometryGenerator{
+		maxSide: max(maxSide, 4),
+		random:  rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Generate creates a new area or perimeter problem
+func (g *GeometryGenerator) Generate() Problem {
+	unit := lengthUnits[g.random.Intn(len(lengthUnits))]
+	symbol := unitSymbols[unit]
+
+	var shape, figure string
+	var area, perimeter int
+	var operands []int
+
+	if g.random.Intn(2) == 0 {
+		width := randomBetween(g.random, 2, g.maxSide)
+		height := randomBetween(g.random, 2, g.maxSide)
+
+		shape = "rectangle"
+		figure = drawRectangle(fmt.Sprintf("%d %s", width, symbol), fmt.Sprintf("%d %s", height, symbol))
+		area = width * height
+		perimeter = 2 * (width + height)
+		operands = []int{width, height}
+	} else {
+		// An L shape is a rectangle with its top-right corner cut out
+		width := randomBetween(g.random, 4, g.maxSide)
+		height := randomBetween(g.random, 4, g.maxSide)
+		cutWidth := randomBetween(g.random, 2, width-2)
+		cutHeight := randomBetween(g.random, 2, height-2)
+
+		label := func(n int) string { return fmt.Sprintf("%d %s", n, symbol) }
+		shape = "shape"
+		figure = drawLShape(label(width-cutWidth), label(cutHeight), label(cutWidth),
+			label(height-cutHeight), label(width), label(height))
+		area = width*height - cutWidth*cutHeight
+		perimeter = 2 * (width + height)
+		operands = []int{width, height, cutWidth, cutHeight}
+	}
+
+	if g.random.Intn(2) == 0 {
+		return Problem{
+			Question:  fmt.Sprintf("What is the area of the %s in %s?", shape, units[squareUnits[unit]].Plural),
+			Answer:    area,
+			Type:      Geometry,
+			Operands:  operands,
+			Statement: true,
+			Unit:      squareUnits[unit],
+			Figure:    figure,
+		}
+	}
+
+	return Problem{
+		Question:  fmt.Sprintf("What is the perimeter of the %s in %s?", shape, units[unit].Plural),
+		Answer:    perimeter,
+		Type:      Geometry,
+		Operands:  operands,
+		Statement: true,
+		Unit:      unit,
+		Figure:    figure,
+	}
+}
+
+// Type returns the type of problems this generator creates
+func (g *GeometryGenerator) Type() ProblemType {
+	return Geometry
+}
+
+// Name returns a human-readable name for this problem type
+func (g *GeometryGenerator) Name() string {
+	return "Area and Perimeter"
+}
+
+// figureMargin leaves room for labels on the left side of a drawing
+const figureMargin = 8
+
+// drawRectangle draws a rectangle with its width labeled above and its
+// height labeled on the right
+func drawRectangle(width, height string) string {
+	const inner = 18
+	pad := strings.Repeat(" ", figureMargin)
+	edge := pad + "+" + strings.Repeat("-", inner) + "+\n"
+	side := pad + "|" + strings.Repeat(" ", inner) + "|"
+
+	var b strings.Builder
+	b.WriteString(pad + " " + center(width, inner) + "\n")
+	b.WriteString(edge)
+	b.WriteString(side + "\n")
+	b.WriteString(side + " " + height + "\n")
+	b.WriteString(side + "\n")
+	b.WriteString(edge)
+	b.WriteString("(not drawn to scale)\n")
+	return b.String()
+}
+
+// drawLShape draws an L shape with all six sides labeled, starting from
+// the top edge and going clockwise, then the bottom and left edges
+func drawLShape(top, cutHeight, cutWidth, rightHeight, bottom, left string) string {
+	const upper, lower = 10, 10
+	pad := strings.Repeat(" ", figureMargin)
+	blank := strings.Repeat(" ", upper)
+
+	var b strings.Builder
+	b.WriteString(pad + " " + center(top, upper) + "\n")
+	b.WriteString(pad + "+" + strings.Repeat("-", upper) + "+\n")
+	b.WriteString(pad + "|" + blank + "| " + cutHeight + "\n")
+	b.WriteString(pad + "|" + blank + "|  " + center(cutWidth, lower-2) + "\n")
+	b.WriteString(fmt.Sprintf("%*s |", figureMargin-1, left) + blank + "+" + strings.Repeat("-", lower) + "+\n")
+	b.WriteString(pad + "|" + strings.Repeat(" ", upper+lower+1) + "| " + rightHeight + "\n")
+	b.WriteString(pad + "+" + strings.Repeat("-", upper+lower+1) + "+\n")
+	b.WriteString(pad + " " + center(bottom, upper+lower+1) + "\n")
+	b.WriteString("(not drawn to scale)\n")
+	return b.String()
+}
+
+// center pads s with spaces to center it in width columns
+func center(s string, width int) string {
+	if len(s) >= width {
+		return s
+	}
+	left := (width - len(s)) / 2
+	return strings.Repeat(" ", left) + s
+}
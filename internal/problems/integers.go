@@ -0,0 +1,86 @@
+package problems
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// IntegersGenerator generates problems for all four operations using
+// negative as well as positive numbers
+type IntegersGenerator struct {
+	maxDigits int
+	maxFactor int
+	random    *rand.Rand
+}
+
+// NewIntegersGenerator creates a new integers problem generator. Addition and
+// subtraction operands have up to maxDigits digits; multiplication and
+// division use factors up to maxFactor.
+func NewIntegersGenerator(maxDigits, maxFactor int) *IntegersGenerator {
+	return &IntegersGenerator{
+		maxDigits: maxDigits,
+		maxFactor: maxFactor,
+		random:    rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Generate creates a new problem with signed operands
+func (g *IntegersGenerator) Generate() Problem {
+	maxNum := pow10(g.maxDigits) - 1
+
+	var num1, num2, answer int
+	var operator string
+
+	switch g.random.Intn(4) {
+	case 0:
+		num1, num2 = g.signed(maxNum), g.signed(maxNum)
+		operator, answer = "+", num1+num2
+	case 1:
+		num1, num2 = g.signed(maxNum), g.signed(maxNum)
+		operator, answer = "-", num1-num2
+	case 2:
+		num1, num2 = g.signed(g.maxFactor), g.signed(g.maxFactor)
+		operator, answer = "×", num1*num2
+	default:
+		// Build the division from a multiplication fact to ensure clean division
+		divisor, quotient := g.signed(g.maxFactor), g.signed(g.maxFactor)
+		num1, num2 = divisor*quotient, divisor
+		operator, answer = "÷", quotient
+	}
+
+	return Problem{
+		Question: fmt.Sprintf("%d %s %s", num1, operator, formatOperand(num2)),
+		Answer:   answer,
+		Type:     Integers,
+		Operands: []int{num1, num2},
+	}
+}
+
+// signed returns a random non-zero integer between -max and max
+func (g *IntegersGenerator) signed(max int) int {
+	n := g.random.Intn(max) + 1
+	if g.random.Intn(2) == 0 {
+		return -n
+	}
+	return n
+}
+
+// formatOperand wraps negative right-hand operands in parentheses so that
+// "5 - (-3)" doesn't read as "5 - -3"
+func formatOperand(n int) string {
+	if n < 0 {
+		return fmt.Sprintf("(%d)", n)
+	}
+	return fmt.Sprintf("%d", n)
+}
+
+// Type returns the type of problems this generator creates
+func (g *IntegersGenerator) Type() ProblemType {
+	return Integers
+}
+
+// Name returns a human-readable name for this problem type
+func (g *IntegersGenerator) Name() string {
+	return "Integers"
+}
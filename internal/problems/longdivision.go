@@ -0,0 +1,113 @@
+package problems
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"time"
+)
+
+// LongDivisionGenerator generates multi-digit division problems with no
+// remainder, optionally broken into divide and subtract steps
+type LongDivisionGenerator struct {
+	dividendDigits int
+	maxDivisor     int
+	steps          bool
+	random         *rand.Rand
+}
+
+// NewLongDivisionGenerator creates a new long division problem generator
+// with dividends of about dividendDigits digits and divisors from 2 to
+// maxDivisor. If steps is true, each problem asks for every divide and
+// subtract step before the final answer.
+func NewLongDivisionGenerator(dividendDigits, maxDivisor int, steps bool) *LongDivisionGenerator {
+	return &LongDivisionGenerator{
+		dividendDigits: dividendDigits,
+		maxDivisor:     maxDivisor,
+		steps:          steps,
+		random:         rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Generate creates a new long division problem
+func (g *LongDivisionGenerator) Generate() Problem {
+	// Build the dividend from a quotient to ensure there is no remainder
+	divisor := randomBetween(g.random, 2, g.maxDivisor)
+	minDividend, maxDividend := pow10(g.dividendDigits-1), pow10(g.dividendDigits)-1
+	quotient := randomBetween(g.random, (minDividend+divisor-1)/divisor, maxDividend/divisor)
+	dividend := quotient * divisor
+
+	problem := Problem{
+		Question: fmt.Sprintf("%d ÷ %d", dividend, divisor),
+		Answer:   quotient,
+		Type:     LongDivision,
+		Operands: []int{dividend, divisor},
+	}
+
+	if g.steps {
+		problem.Steps = divisionSteps(dividend, divisor)
+	}
+
+	return problem
+}
+
+// divisionSteps returns a divide step and a subtract step for each digit of
+// the quotient, bringing down the next digit of the dividend each time
+func divisionSteps(dividend, divisor int) []Step {
+	var steps []Step
+	digits := strconv.Itoa(dividend)
+	current := 0
+
+	for i := 0; i < len(digits); i++ {
+		// Bring down the next digit
+		current = current*10 + int(digits[i]-'0')
+		if current < divisor && len(steps) == 0 {
+			continue
+		}
+
+		part := current
+		times := part / divisor
+		product := times * divisor
+		remainder := part - product
+
+		steps = append(steps, Step{
+			Prompt: fmt.Sprintf("How many times does %d go into %d?", divisor, part),
+			Answer: times,
+			Feedback: func(answer int) string {
+				switch {
+				case answer > times:
+					return fmt.Sprintf("Too many: %d × %d = %d is more than %d. %d goes in %d times.",
+						divisor, answer, divisor*answer, part, divisor, times)
+				case answer >= 0:
+					return fmt.Sprintf("Too few: %d × %d = %d leaves %d, which is still %d or more. %d goes in %d times.",
+						divisor, answer, divisor*answer, part-divisor*answer, divisor, divisor, times)
+				default:
+					return fmt.Sprintf("%d goes into %d %d times.", divisor, part, times)
+				}
+			},
+		})
+
+		steps = append(steps, Step{
+			Prompt: fmt.Sprintf("Subtract %d × %d from %d: %d - %d = ?", divisor, times, part, part, product),
+			Answer: remainder,
+			Feedback: func(answer int) string {
+				return fmt.Sprintf("Not quite. %d × %d = %d, and %d - %d = %d.",
+					divisor, times, product, part, product, remainder)
+			},
+		})
+
+		current = remainder
+	}
+
+	return steps
+}
+
+// Type returns the type of problems this generator creates
+func (g *LongDivisionGenerator) Type() ProblemType {
+	return LongDivision
+}
+
+// Name returns a human-readable name for this problem type
+func (g *LongDivisionGenerator) Name() string {
+	return "Long Division"
+}
@@ -0,0 +1,136 @@
+package problems
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LongMultiplicationGenerator generates multi-digit multiplication problems
+// such as 3-digit × 2-digit, optionally broken into partial-product steps
+type LongMultiplicationGenerator struct {
+	topDigits    int
+	bottomDigits int
+	steps        bool
+	random       *rand.Rand
+}
+
+// NewLongMultiplicationGenerator creates a new long multiplication problem
+// generator. If steps is true, each problem asks for every partial product
+// before the final answer.
+func NewLongMultiplicationGenerator(topDigits, bottomDigits int, steps bool) *LongMultiplicationGenerator {
+	return &LongMultiplicationGenerator{
+		topDigits:    topDigits,
+		bottomDigits: bottomDigits,
+		steps:        steps,
+		random:       rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Generate creates a new long multiplication problem
+func (g *LongMultiplicationGenerator) Generate() Problem {
+	top := randomBetween(g.random, pow10(g.topDigits-1), pow10(g.topDigits)-1)
+	bottom := randomBetween(g.random, pow10(g.bottomDigits-1), pow10(g.bottomDigits)-1)
+
+	problem := Problem{
+		Question: fmt.Sprintf("%d × %d", top, bottom),
+		Answer:   top * bottom,
+		Type:     LongMultiplication,
+		Operands: []int{top, bottom},
+		Figure:   drawVertical(top, bottom, "×"),
+	}
+
+	if g.steps {
+		problem.Steps = multiplicationSteps(top, bottom)
+	}
+
+	return problem
+}
+
+// multiplicationSteps returns one step per partial product, working from
+// the ones digit of bottom upwards
+func multiplicationSteps(top, bottom int) []Step {
+	var steps []Step
+
+	for place, power := 0, 1; power <= bottom; place, power = place+1, power*10 {
+		digit := bottom / power % 10
+		partial := top * digit * power
+
+		steps = append(steps, Step{
+			Prompt: fmt.Sprintf("Multiply %d by the %s digit: %d × %d = ?", top, placeNames[place], top, digit*power),
+			Answer: partial,
+			Feedback: func(answer int) string {
+				if place > 0 && answer == top*digit {
+					return fmt.Sprintf("Don't forget the placeholder zero%s: the %d stands for %d, so the answer is %d.",
+						pluralSuffix(place), digit, digit*power, partial)
+				}
+				return fmt.Sprintf("Not quite. %s", explainDigitProduct(top, digit, power))
+			},
+		})
+	}
+
+	return steps
+}
+
+// pluralSuffix returns "s" when a count of placeholder zeros is not one
+func pluralSuffix(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return "s"
+}
+
+// explainDigitProduct walks through multiplying n by a single digit from
+// right to left, showing each carry
+func explainDigitProduct(n, digit, power int) string {
+	digits := strconv.Itoa(n)
+	var parts []string
+	carry := 0
+
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		product := d*digit + carry
+
+		part := fmt.Sprintf("%d × %d = %d", digit, d, d*digit)
+		if carry > 0 {
+			part += fmt.Sprintf(", plus %d carried is %d", carry, product)
+		}
+		if i > 0 && product >= 10 {
+			part += fmt.Sprintf(", write %d and carry %d", product%10, product/10)
+		}
+		parts = append(parts, part)
+		carry = product / 10
+	}
+
+	explanation := fmt.Sprintf("Multiply %d by each digit from right to left: %s. So %d × %d = %d",
+		digit, strings.Join(parts, "; "), n, digit, n*digit)
+	if power > 1 {
+		zeros := len(strconv.Itoa(power)) - 1
+		explanation += fmt.Sprintf(", and with the placeholder zero%s %d × %d = %d",
+			pluralSuffix(zeros), n, digit*power, n*digit*power)
+	}
+	return explanation + "."
+}
+
+// drawVertical lays out a two-number problem the way it's written on paper
+func drawVertical(top, bottom int, operator string) string {
+	topText, bottomText := strconv.Itoa(top), strconv.Itoa(bottom)
+	width := max(len(topText), len(bottomText)) + 2
+
+	return fmt.Sprintf("  %*s\n  %s%*s\n  %s\n",
+		width, topText,
+		operator, width-1, bottomText,
+		strings.Repeat("-", width))
+}
+
+// Type returns the type of problems this generator creates
+func (g *LongMultiplicationGenerator) Type() ProblemType {
+	return LongMultiplication
+}
+
+// Name returns a human-readable name for this problem type
+func (g *LongMultiplicationGenerator) Name() string {
+	return "Long Multiplication"
+}
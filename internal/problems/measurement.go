@@ -0,0 +1,166 @@
+package problems
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// MeasurementSystem is a system of measurement units
+type MeasurementSystem string
+
+const (
+	Metric    MeasurementSystem = "metric"
+	Customary MeasurementSystem = "customary"
+)
+
+// Unit describes a unit of measurement an answer can be given in
+type Unit struct {
+	Name    string
+	Plural  string
+	Aliases []string
+
+	// System is empty for units such as minutes that every system shares
+	System MeasurementSystem
+}
+
+// units holds the known units, keyed by name
+var units = map[string]Unit{
+	"inch":       {Name: "inch", Plural: "inches", Aliases: []string{"in", "in.", "\""}, System: Customary},
+	"foot":       {Name: "foot", Plural: "feet", Aliases: []string{"ft", "ft.", "'"}, System: Customary},
+	"yard":       {Name: "yard", Plural: "yards", Aliases: []string{"yd", "yd.", "yds"}, System: Customary},
+	"centimeter": {Name: "centimeter", Plural: "centimeters", Aliases: []string{"cm", "centimetre", "centimetres"}, System: Metric},
+	"meter":      {Name: "meter", Plural: "meters", Aliases: []string{"m", "metre", "metres"}, System: Metric},
+	"gram":       {Name: "gram", Plural: "grams", Aliases: []string{"g"}, System: Metric},
+	"kilogram":   {Name: "kilogram", Plural: "kilograms", Aliases: []string{"kg", "kgs"}, System: Metric},
+	"minute":     {Name: "minute", Plural: "minutes", Aliases: []string{"min", "mins"}},
+	"hour":       {Name: "hour", Plural: "hours", Aliases: []string{"h", "hr", "hrs"}},
+
+	// Percentages are answered like a unit
+	"percent": {Name: "percent", Plural: "percent", Aliases: []string{"%", "pct"}},
+
+	// Units of area
+	"square centimeter": {Name: "square centimeter", Plural: "square centimeters", Aliases: []string{"sq cm", "cm2", "cm²"}, System: Metric},
+	"square meter":      {Name: "square meter", Plural: "square meters", Aliases: []string{"sq m", "m2", "m²"}, System: Metric},
+	"square inch":       {Name: "square inch", Plural: "square inches", Aliases: []string{"sq in", "in2", "in²"}, System: Customary},
+	"square foot":       {Name: "square foot", Plural: "square feet", Aliases: []string{"sq ft", "ft2", "ft²"}, System: Customary},
+}
+
+// LookupUnit returns the unit with the given name
+func LookupUnit(name string) (Unit, bool) {
+	u, ok := units[name]
+	return u, ok
+}
+
+// Format returns quantity followed by the unit name that agrees with it
+func (u Unit) Format(quantity string, count int) string {
+	if count == 1 {
+		return quantity + " " + u.Name
+	}
+	return quantity + " " + u.Plural
+}
+
+// matches reports whether s names this unit
+func (u Unit) matches(s string) bool {
+	s = strings.ToLower(s)
+	if s == u.Name || s == u.Plural {
+		return true
+	}
+	for _, alias := range u.Aliases {
+		if s == alias {
+			return true
+		}
+	}
+	return false
+}
+
+// conversion relates a larger unit to a smaller one
+type conversion struct {
+	large  string
+	small  string
+	factor int
+}
+
+// conversions lists the unit conversions the generator can ask about
+var conversions = []conversion{
+	{large: "foot", small: "inch", factor: 12},
+	{large: "yard", small: "foot", factor: 3},
+	{large: "yard", small: "inch", factor: 36},
+	{large: "meter", small: "centimeter", factor: 100},
+	{large: "kilogram", small: "gram", factor: 1000},
+	{large: "hour", small: "minute", factor: 60},
+}
+
+// MeasurementGenerator generates unit conversion problems
+type MeasurementGenerator struct {
+	maxQuantity int
+	conversions []conversion
+	random      *rand.Rand
+}
+
+// NewMeasurementGenerator creates a new unit conversion problem generator.
+// Quantities of the larger unit go up to maxQuantity. If no systems are
+// given, both metric and US customary units are used.
+func NewMeasurementGenerator(maxQuantity int, systems ...MeasurementSystem) *MeasurementGenerator {
+	var selected []conversion
+	for _, c := range conversions {
+		system := units[c.large].System
+		if len(systems) == 0 || system == "" || containsSystem(systems, system) {
+			selected = append(selected, c)
+		}
+	}
+
+	return &MeasurementGenerator{
+		maxQuantity: maxQuantity,
+		conversions: selected,
+		random:      rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// containsSystem reports whether systems includes system
+func containsSystem(systems []MeasurementSystem, system MeasurementSystem) bool {
+	for _, s := range systems {
+		if s == system {
+			return true
+		}
+	}
+	return false
+}
+
+// Generate creates a new unit conversion problem
+func (g *MeasurementGenerator) Generate() Problem {
+	c := g.conversions[g.random.Intn(len(g.conversions))]
+	large, small := units[c.large], units[c.small]
+	quantity := g.random.Intn(g.maxQuantity) + 1
+
+	// Convert down to the smaller unit, or up from an exact multiple of it
+	if g.random.Intn(2) == 0 {
+		return Problem{
+			Question: large.Format(fmt.Sprintf("%d", quantity), quantity),
+			Answer:   quantity * c.factor,
+			Type:     Measurement,
+			Operands: []int{quantity, c.factor},
+			Unit:     small.Name,
+		}
+	}
+
+	amount := quantity * c.factor
+	return Problem{
+		Question: small.Format(fmt.Sprintf("%d", amount), amount),
+		Answer:   quantity,
+		Type:     Measurement,
+		Operands: []int{amount, c.factor},
+		Unit:     large.Name,
+	}
+}
+
+// Type returns the type of problems this generator creates
+func (g *MeasurementGenerator) Type() ProblemType {
+	return Measurement
+}
+
+// Name returns a human-readable name for this problem type
+func (g *MeasurementGenerator) Name() string {
+	return "Measurement"
+}
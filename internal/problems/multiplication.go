@@ -3,12 +3,14 @@ package problems
 import (
 	"fmt"
 	"math/rand"
+	"slices"
 	"time"
 )
 
 // MultiplicationGenerator generates multiplication problems
 type MultiplicationGenerator struct {
 	maxFactor int
+	tables    []int
 	random    *rand.Rand
 }
 
@@ -20,17 +22,79 @@ func NewMultiplicationGenerator(maxFactor int) *MultiplicationGenerator {
 	}
 }
 
+// NewMultiplicationTablesGenerator creates a multiplication problem
+// generator for the facts of the given times tables only, such as all facts
+// with a 9. A nil list practices every table.
+func NewMultiplicationTablesGenerator(maxFactor int, tables []int) (*MultiplicationGenerator, error) {
+	if err := checkTables(tables, maxFactor); err != nil {
+		return nil, err
+	}
+
+	g := NewMultiplicationGenerator(maxFactor)
+	g.tables = slices.Clone(tables)
+	return g, nil
+}
+
 // Generate creates a new multiplication problem
 func (g *MultiplicationGenerator) Generate() Problem {
 	// Generate two random factors from 1 to maxFactor
 	factor1 := g.random.Intn(g.maxFactor) + 1
 	factor2 := g.random.Intn(g.maxFactor) + 1
 
+	// With tables selected, one factor comes from them, on either side
+	if len(g.tables) > 0 {
+		factor1 = g.tables[g.random.Intn(len(g.tables))]
+		if g.random.Intn(2) == 0 {
+			factor1, factor2 = factor2, factor1
+		}
+	}
+
+	return multiplicationFact(factor1, factor2)
+}
+
+// multiplicationFact creates the problem factor1 × factor2
+func multiplicationFact(factor1, factor2 int) Problem {
 	return Problem{
 		Question: fmt.Sprintf("%d × %d", factor1, factor2),
 		Answer:   factor1 * factor2,
 		Type:     Multiplication,
+		Operands: []int{factor1, factor2},
+	}
+}
+
+// Enumerate returns every multiplication fact up to maxFactor, one row per
+// table. Rows of selected tables include each fact in both orders.
+func (g *MultiplicationGenerator) Enumerate() [][]Problem {
+	rows := make([][]Problem, 0, g.maxFactor)
+	for _, table := range g.practiced() {
+		row := make([]Problem, 0, 2*g.maxFactor)
+		for factor := 1; factor <= g.maxFactor; factor++ {
+			row = append(row, multiplicationFact(table, factor))
+			if len(g.tables) > 0 && factor != table {
+				row = append(row, multiplicationFact(factor, table))
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// practiced returns the selected tables, or every table up to maxFactor
+func (g *MultiplicationGenerator) practiced() []int {
+	if len(g.tables) > 0 {
+		return g.tables
 	}
+
+	tables := make([]int, g.maxFactor)
+	for i := range tables {
+		tables[i] = i + 1
+	}
+	return tables
+}
+
+// Tables returns the times tables being practiced, or nil for all of them
+func (g *MultiplicationGenerator) Tables() []int {
+	return slices.Clone(g.tables)
 }
 
 // Type returns the type of problems this generator creates
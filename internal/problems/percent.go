@@ -0,0 +1,149 @@
+package problems
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// percentChoices lists the percentages used at each difficulty level
+var percentChoices = map[int][]int{
+	1: {10, 25, 50, 75, 100},
+	2: {5, 10, 15, 20, 25, 30, 40, 50, 60, 70, 75, 80, 90},
+	3: {1, 2, 4, 5, 8, 12, 15, 20, 24, 35, 40, 45, 55, 64, 65, 75, 85, 95, 120, 150},
+}
+
+// PercentGenerator generates percent-of-a-number, percent change and
+// ratio problems, all with whole-number answers
+type PercentGenerator struct {
+	difficulty int
+	random     *rand.Rand
+}
+
+// NewPercentGenerator creates a new percentage and ratio problem generator.
+// Difficulty runs from 1 (friendly percentages like 25%) to 3.
+func NewPercentGenerator(difficulty int) *PercentGenerator {
+	return &PercentGenerator{
+		difficulty: min(max(difficulty, 1), 3),
+		random:     rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Generate creates a new percentage or ratio problem
+func (g *PercentGenerator) Generate() Problem {
+	kinds := 2
+	if g.difficulty > 1 {
+		// Percent change needs a feel for percent-of first
+		kinds = 3
+	}
+
+	switch g.random.Intn(kinds) {
+	case 0:
+		return g.percentOf()
+	case 1:
+		return g.ratio()
+	default:
+		return g.percentChange()
+	}
+}
+
+// percent picks a percentage for the current difficulty
+func (g *PercentGenerator) percent() int {
+	choices := percentChoices[g.difficulty]
+	return choices[g.random.Intn(len(choices))]
+}
+
+// wholeBase returns a random number that p percent of is a whole number
+func (g *PercentGenerator) wholeBase(p int) int {
+	step := 100 / gcd(p, 100)
+	return step * (g.random.Intn(10*g.difficulty) + 1)
+}
+
+// percentOf creates a "what is 25% of 80?" problem
+func (g *PercentGenerator) percentOf() Problem {
+	p := g.percent()
+	base := g.wholeBase(p)
+
+	return Problem{
+		Question:  fmt.Sprintf("What is %d%% of %d?", p, base),
+		Answer:    base * p / 100,
+		Type:      Percent,
+		Operands:  []int{p, base},
+		Statement: true,
+	}
+}
+
+// percentChange creates a percent increase or decrease problem
+func (g *PercentGenerator) percentChange() Problem {
+	p := g.percent()
+	start := g.wholeBase(p)
+
+	if p < 100 && g.random.Intn(2) == 0 {
+		end := start * (100 - p) / 100
+		return Problem{
+			Question:  fmt.Sprintf("A price drops from $%d to $%d. What is the percent decrease?", start, end),
+			Answer:    p,
+			Type:      Percent,
+			Operands:  []int{start, end},
+			Statement: true,
+			Unit:      "percent",
+		}
+	}
+
+	end := start * (100 + p) / 100
+	return Problem{
+		Question:  fmt.Sprintf("A plant grows from %d cm to %d cm tall. What is the percent increase?", start, end),
+		Answer:    p,
+		Type:      Percent,
+		Operands:  []int{start, end},
+		Statement: true,
+		Unit:      "percent",
+	}
+}
+
+// ratio creates an "a : b = c : __" proportion problem
+func (g *PercentGenerator) ratio() Problem {
+	maxTerm := 5 * g.difficulty
+	a := g.random.Intn(maxTerm) + 1
+	b := g.random.Intn(maxTerm) + 1
+	divisor := gcd(a, b)
+	a, b = a/divisor, b/divisor
+
+	scale := g.random.Intn(3*g.difficulty) + 2
+
+	// Hide either the second or the fourth term
+	if g.random.Intn(2) == 0 {
+		return Problem{
+			Question:  fmt.Sprintf("%d : %d = %d : __", a, b, a*scale),
+			Answer:    b * scale,
+			Type:      Percent,
+			Operands:  []int{a, b, a * scale, b * scale},
+			Statement: true,
+		}
+	}
+	return Problem{
+		Question:  fmt.Sprintf("%d : __ = %d : %d", a, a*scale, b*scale),
+		Answer:    b,
+		Type:      Percent,
+		Operands:  []int{a, b, a * scale, b * scale},
+		Statement: true,
+	}
+}
+
+// gcd returns the greatest common divisor of a and b
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// Type returns the type of problems this generator creates
+func (g *PercentGenerator) Type() ProblemType {
+	return Percent
+}
+
+// Name returns a human-readable name for this problem type
+func (g *PercentGenerator) Name() string {
+	return "Percentages and Ratios"
+}
@@ -0,0 +1,106 @@
+package problems
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"time"
+)
+
+// placeNames names each place value, indexed by its power of ten
+var placeNames = []string{
+	"ones",
+	"tens",
+	"hundreds",
+	"thousands",
+	"ten thousands",
+	"hundred thousands",
+	"millions",
+}
+
+// maxPlaceNumber is the largest number whose every place has a name
+var maxPlaceNumber = pow10(len(placeNames)) - 1
+
+// placeRange clamps a range of numbers to those with named places, from 0 to
+// maxPlaceNumber
+func placeRange(min, max int) (int, int) {
+	return clamp(min, 0, maxPlaceNumber), clamp(max, 0, maxPlaceNumber)
+}
+
+// clamp limits n to the range from low to high
+func clamp(n, low, high int) int {
+	return min(max(n, low), high)
+}
+
+// PlaceValueGenerator generates "what digit is in the ___ place" problems
+type PlaceValueGenerator struct {
+	min    int
+	max    int
+	random *rand.Rand
+}
+
+// NewPlaceValueGenerator creates a new place value problem generator using
+// numbers from min to max, limited to 0 through 9,999,999
+func NewPlaceValueGenerator(min, max int) *PlaceValueGenerator {
+	min, max = placeRange(min, max)
+	return &PlaceValueGenerator{
+		min:    min,
+		max:    max,
+		random: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Generate creates a new place value problem
+func (g *PlaceValueGenerator) Generate() Problem {
+	number := randomBetween(g.random, g.min, g.max)
+
+	// Pick one of the places the number actually has
+	digits := len(strconv.Itoa(number))
+	place := g.random.Intn(digits)
+	digit := number / pow10(place) % 10
+
+	return Problem{
+		Question:  fmt.Sprintf("What digit is in the %s place of %s?", placeNames[place], FormatThousands(number)),
+		Answer:    digit,
+		Type:      PlaceValue,
+		Operands:  []int{number, pow10(place)},
+		Statement: true,
+	}
+}
+
+// Type returns the type of problems this generator creates
+func (g *PlaceValueGenerator) Type() ProblemType {
+	return PlaceValue
+}
+
+// Name returns a human-readable name for this problem type
+func (g *PlaceValueGenerator) Name() string {
+	return "Place Value"
+}
+
+// randomBetween returns a random integer from min to max inclusive
+func randomBetween(random *rand.Rand, min, max int) int {
+	if max <= min {
+		return min
+	}
+	return min + random.Intn(max-min+1)
+}
+
+// roundTo rounds n to the nearest multiple of place, rounding halves up
+func roundTo(n, place int) int {
+	return (n + place/2) / place * place
+}
+
+// FormatThousands formats n with commas between groups of three digits,
+// e.g. 5382 becomes "5,382"
+func FormatThousands(n int) string {
+	if n < 0 {
+		return "-" + FormatThousands(-n)
+	}
+
+	s := strconv.Itoa(n)
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
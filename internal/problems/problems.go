@@ -1,13 +1,35 @@
 package problems
 
+import "fmt"
+
 // ProblemType represents the type of math problem
 type ProblemType string
 
 const (
-	Addition       ProblemType = "addition"
-	Subtraction    ProblemType = "subtraction"
-	Multiplication ProblemType = "multiplication"
-	Division       ProblemType = "division"
+	Addition           ProblemType = "addition"
+	Subtraction        ProblemType = "subtraction"
+	Multiplication     ProblemType = "multiplication"
+	Division           ProblemType = "division"
+	Decimal            ProblemType = "decimal"
+	Money              ProblemType = "money"
+	Integers           ProblemType = "integers"
+	WordProblem        ProblemType = "word"
+	PlaceValue         ProblemType = "place-value"
+	Rounding           ProblemType = "rounding"
+	Estimation         ProblemType = "estimation"
+	Comparison         ProblemType = "comparison"
+	Time               ProblemType = "time"
+	Measurement        ProblemType = "measurement"
+	Geometry           ProblemType = "geometry"
+	Sequence           ProblemType = "sequence"
+	LongMultiplication ProblemType = "long-multiplication"
+	LongDivision       ProblemType = "long-division"
+	Exponent           ProblemType = "exponent"
+	Percent            ProblemType = "percent"
+
+	// Daily is the mixed daily challenge. It isn't in the registry, since it
+	// has no settings and is played from its own menu item.
+	Daily ProblemType = "daily"
 )
 
 // Problem represents a single math problem
@@ -15,6 +37,68 @@ type Problem struct {
 	Question string
 	Answer   int
 	Type     ProblemType
+
+	// Kind says how Answer is encoded and how user input is parsed. The
+	// zero value is NumberAnswer.
+	Kind AnswerKind
+
+	// Decimals is the number of fixed-point decimal places in Answer.
+	// For example, with Decimals set to 2 an Answer of 525 means 5.25.
+	Decimals int
+
+	// Operands holds the numbers the problem was built from, in the order
+	// they appear in Question
+	Operands []int
+
+	// Statement marks problems whose Question is a complete sentence rather
+	// than an expression to be completed with "= ?"
+	Statement bool
+
+	// Tolerance is how far an answer may be from Answer and still count as
+	// correct, for problems such as estimates that have no single answer
+	Tolerance int
+
+	// Thousands marks problems whose numbers are shown with commas between
+	// groups of three digits, so the answer is shown the same way
+	Thousands bool
+
+	// Unit is the name of the unit the answer must be given in, if any
+	Unit string
+
+	// Figure is an optional ASCII drawing shown above the question
+	Figure string
+
+	// Steps are the intermediate results checked in step-by-step mode,
+	// asked in order before the final answer
+	Steps []Step
+}
+
+// Step is one intermediate result of a worked problem, such as a partial
+// product in long multiplication
+type Step struct {
+	Prompt string
+	Answer int
+
+	// Feedback explains what went wrong when the step is answered incorrectly
+	Feedback func(answer int) string
+}
+
+// Explain returns feedback for a wrong answer to the step
+func (s Step) Explain(answer int) string {
+	if s.Feedback == nil {
+		return fmt.Sprintf("Not quite. The answer is %d.", s.Answer)
+	}
+	return s.Feedback(answer)
+}
+
+// Problem returns the step as a problem that can be asked on its own
+func (s Step) Problem(parent Problem) Problem {
+	return Problem{
+		Question:  s.Prompt,
+		Answer:    s.Answer,
+		Type:      parent.Type,
+		Statement: true,
+	}
 }
 
 // String returns a string representation of the problem
@@ -22,6 +106,20 @@ func (p Problem) String() string {
 	return p.Question
 }
 
+// Prompt returns the text shown when asking for the answer
+func (p Problem) Prompt() string {
+	if hint := p.format().hint; hint != "" {
+		return fmt.Sprintf("%s  (%s)", p.Question, hint)
+	}
+	if p.Statement {
+		return p.Question
+	}
+	if u, ok := LookupUnit(p.Unit); ok {
+		return fmt.Sprintf("%s = ? %s", p.Question, u.Plural)
+	}
+	return p.Question + " = ?"
+}
+
 // Generator defines the interface for problem generators
 type Generator interface {
 	// Generate creates a new math problem
@@ -33,3 +131,17 @@ type Generator interface {
 	// Name returns a human-readable name for this problem type
 	Name() string
 }
+
+// FixedLength is implemented by generators with a set number of problems,
+// such as quizzes, so that a session asks each of them once
+type FixedLength interface {
+	// Len returns the number of problems
+	Len() int
+}
+
+// Seeder is implemented by generators whose random source can be reseeded
+// to produce a repeatable set of problems
+type Seeder interface {
+	// Seed resets the generator's random source
+	Seed(seed int64)
+}
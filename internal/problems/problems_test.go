@@ -2,7 +2,12 @@ package problems
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestMultiplicationGenerator(t *testing.T) {
@@ -181,3 +186,1694 @@ func TestSubtractionGenerator(t *testing.T) {
 		}
 	}
 }
+
+func TestDecimalGenerator(t *testing.T) {
+	generator := NewDecimalGenerator(2, 2)
+
+	// Test type and name
+	if generator.Type() != Decimal {
+		t.Errorf("Expected problem type %s, got %s", Decimal, generator.Type())
+	}
+
+	if generator.Name() != "Decimals" {
+		t.Errorf("Expected name 'Decimals', got '%s'", generator.Name())
+	}
+
+	// Generate and test 100 problems
+	for i := 0; i < 100; i++ {
+		problem := generator.Generate()
+
+		if problem.Type != Decimal {
+			t.Errorf("Expected problem type %s, got %s", Decimal, problem.Type)
+		}
+		if problem.Decimals != 2 {
+			t.Errorf("Expected 2 decimal places, got %d", problem.Decimals)
+		}
+
+		// Parse problem and verify answer using fixed-point cents
+		var left, operator, right string
+		n, err := fmt.Sscanf(problem.Question, "%s %s %s", &left, &operator, &right)
+		if err != nil || n != 3 {
+			t.Errorf("Failed to parse problem: %s", problem.Question)
+			continue
+		}
+
+		num1, err1 := ParseDecimal(left, 2)
+		num2, err2 := ParseDecimal(right, 2)
+		if err1 != nil || err2 != nil {
+			t.Errorf("Failed to parse operands: %s", problem.Question)
+			continue
+		}
+
+		var expectedAnswer int
+		switch operator {
+		case "+":
+			expectedAnswer = num1 + num2
+		case "-":
+			expectedAnswer = num1 - num2
+		default:
+			t.Errorf("Unexpected operator in problem: %s", problem.Question)
+			continue
+		}
+
+		if problem.Answer != expectedAnswer {
+			t.Errorf("Problem: %s, expected answer %d, got %d", problem.Question, expectedAnswer, problem.Answer)
+		}
+		if expectedAnswer < 0 {
+			t.Errorf("Negative answer: %d", expectedAnswer)
+		}
+	}
+}
+
+func TestMoneyGenerator(t *testing.T) {
+	generator := NewMoneyGenerator(1)
+
+	if generator.Type() != Money {
+		t.Errorf("Expected problem type %s, got %s", Money, generator.Type())
+	}
+
+	for i := 0; i < 100; i++ {
+		problem := generator.Generate()
+
+		if !strings.HasPrefix(problem.Question, "$") {
+			t.Errorf("Expected money amounts in problem: %s", problem.Question)
+		}
+
+		// The formatted answer must parse back to the same value
+		answer, err := problem.ParseAnswer(problem.FormatAnswer())
+		if err != nil || answer != problem.Answer {
+			t.Errorf("Answer %s did not round-trip: got %d, %v", problem.FormatAnswer(), answer, err)
+		}
+	}
+}
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    int
+		wantErr bool
+	}{
+		{"5.25", 525, false},
+		{"$5.25", 525, false},
+		{"-$5.25", -525, false},
+		{"$-5.25", -525, false},
+		{"$–5.25", -525, false},
+		{"$$5.25", 0, true},
+		{"5.250", 525, false},
+		{"5.2", 520, false},
+		{"5", 500, false},
+		{".75", 75, false},
+		{"0.10", 10, false},
+		{"5.251", 0, true},
+		{"5.2.5", 0, true},
+		{"abc", 0, true},
+		{"", 0, true},
+		{".", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseDecimal(tt.input, 2)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseDecimal(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseDecimal(%q) = %d, want %d", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestIntegersGeneratorSigns(t *testing.T) {
+	generator := NewIntegersGenerator(2, 12)
+
+	if generator.Type() != Integers {
+		t.Errorf("Expected problem type %s, got %s", Integers, generator.Type())
+	}
+
+	sawNegativeOperand, sawNegativeAnswer := false, false
+
+	// Generate many problems and check the sign rules for each operation
+	for i := 0; i < 1000; i++ {
+		problem := generator.Generate()
+
+		var num1, num2 int
+		var operator string
+		question := strings.NewReplacer("(", "", ")", "").Replace(problem.Question)
+		n, err := fmt.Sscanf(question, "%d %s %d", &num1, &operator, &num2)
+		if err != nil || n != 3 {
+			t.Errorf("Failed to parse problem: %s", problem.Question)
+			continue
+		}
+
+		if num1 < 0 || num2 < 0 {
+			sawNegativeOperand = true
+		}
+		if problem.Answer < 0 {
+			sawNegativeAnswer = true
+		}
+
+		switch operator {
+		case "+":
+			if problem.Answer != num1+num2 {
+				t.Errorf("Problem: %s, expected answer %d, got %d", problem.Question, num1+num2, problem.Answer)
+			}
+		case "-":
+			if problem.Answer != num1-num2 {
+				t.Errorf("Problem: %s, expected answer %d, got %d", problem.Question, num1-num2, problem.Answer)
+			}
+		case "×", "÷":
+			// The product or quotient is negative exactly when the signs differ
+			if (problem.Answer < 0) != ((num1 < 0) != (num2 < 0)) {
+				t.Errorf("Problem: %s has wrong sign in answer %d", problem.Question, problem.Answer)
+			}
+			if operator == "×" && problem.Answer != num1*num2 {
+				t.Errorf("Problem: %s, expected answer %d, got %d", problem.Question, num1*num2, problem.Answer)
+			}
+			if operator == "÷" && (num1%num2 != 0 || problem.Answer != num1/num2) {
+				t.Errorf("Problem: %s, expected answer %d, got %d", problem.Question, num1/num2, problem.Answer)
+			}
+		default:
+			t.Errorf("Unexpected operator in problem: %s", problem.Question)
+		}
+
+		// Negative right-hand operands must be parenthesized
+		if num2 < 0 && !strings.HasSuffix(problem.Question, ")") {
+			t.Errorf("Negative operand not parenthesized: %s", problem.Question)
+		}
+	}
+
+	if !sawNegativeOperand || !sawNegativeAnswer {
+		t.Errorf("Expected some negative operands and answers")
+	}
+}
+
+func TestParseAnswerNegative(t *testing.T) {
+	problem := Problem{Answer: -12, Type: Integers}
+
+	for _, input := range []string{"-12", "–12", " -12 ", "−12"} {
+		answer, err := problem.ParseAnswer(input)
+		if err != nil || answer != -12 {
+			t.Errorf("ParseAnswer(%q) = %d, %v; want -12", input, answer, err)
+		}
+	}
+
+	decimal := Problem{Decimals: 2}
+	if answer, err := decimal.ParseAnswer("–5.25"); err != nil || answer != -525 {
+		t.Errorf("ParseAnswer(en-dash decimal) = %d, %v; want -525", answer, err)
+	}
+}
+
+func TestParseAnswerThousands(t *testing.T) {
+	problem := Problem{Question: "Round 5,382 to the nearest hundred.", Answer: 5400, Type: Rounding, Thousands: true}
+
+	tests := []struct {
+		input   string
+		want    int
+		wantErr bool
+	}{
+		{"5,400", 5400, false},
+		{"5400", 5400, false},
+		{"-1,000,000", -1000000, false},
+		{"54abc", 0, true},
+		{"5.4", 0, true},
+		{"", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := problem.ParseAnswer(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseAnswer(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseAnswer(%q) = %d, want %d", tt.input, got, tt.want)
+		}
+	}
+
+	if got := problem.FormatAnswer(); got != "5,400" {
+		t.Errorf("FormatAnswer() = %q, want %q", got, "5,400")
+	}
+	problem.Tolerance = 500
+	if got, want := problem.FormatAnswer(), "about 5,400 (anything from 4,900 to 5,900)"; got != want {
+		t.Errorf("FormatAnswer() = %q, want %q", got, want)
+	}
+}
+
+func TestWordProblemGenerator(t *testing.T) {
+	library, err := DefaultWordLibrary()
+	if err != nil {
+		t.Fatalf("Failed to load default library: %v", err)
+	}
+
+	generator, err := NewWordProblemGenerator(library, 3)
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+
+	if generator.Type() != WordProblem {
+		t.Errorf("Expected problem type %s, got %s", WordProblem, generator.Type())
+	}
+
+	for i := 0; i < 100; i++ {
+		problem := generator.Generate()
+
+		if problem.Type != WordProblem || !problem.Statement {
+			t.Errorf("Expected a word problem statement, got %+v", problem)
+		}
+		if strings.Contains(problem.Question, "{{") || !strings.HasSuffix(problem.Question, "?") {
+			t.Errorf("Template not filled in: %s", problem.Question)
+		}
+		if len(problem.Operands) != 2 {
+			t.Errorf("Expected two operands, got %v", problem.Operands)
+			continue
+		}
+
+		// Both operands must appear in the story
+		for _, operand := range problem.Operands {
+			if !strings.Contains(problem.Question, fmt.Sprintf("%d", operand)) {
+				t.Errorf("Operand %d missing from: %s", operand, problem.Question)
+			}
+		}
+	}
+}
+
+func TestWordProblemGeneratorGrade(t *testing.T) {
+	library := &WordLibrary{
+		Names: []string{"Maya", "Leo"},
+		Items: []string{"sticker"},
+		Units: []string{"bag"},
+		Templates: []WordTemplate{
+			{Operation: Addition, Grade: 1, Text: "{{.Name}} adds {{.A}} and {{.B}}."},
+			{Operation: Division, Grade: 4, Text: "{{.Name}} divides {{.A}} by {{.B}}."},
+		},
+	}
+
+	generator, err := NewWordProblemGenerator(library, 2)
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+
+	// Only the grade 1 addition template should be used
+	for i := 0; i < 20; i++ {
+		problem := generator.Generate()
+		if problem.Answer != problem.Operands[0]+problem.Operands[1] {
+			t.Errorf("Expected an addition problem, got: %s", problem.Question)
+		}
+	}
+
+	if _, err := NewWordProblemGenerator(library, 0); err == nil {
+		t.Errorf("Expected an error when no templates match the grade")
+	}
+}
+
+func TestWordLibraryRejectsBrokenTemplates(t *testing.T) {
+	// An unknown field parses but fails when the template runs
+	path := filepath.Join(t.TempDir(), "word_problems.json")
+	data := `{"templates": [{"operation": "addition", "grade": 1, "text": "{{.Nmae}} has {{.A}}."}]}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatalf("Failed to write library: %v", err)
+	}
+	if _, err := LoadWordLibrary(path); err == nil {
+		t.Error("Expected an error for a template with an unknown field")
+	}
+
+	library := &WordLibrary{
+		Names:     []string{"Maya", "Leo"},
+		Items:     []string{"sticker"},
+		Units:     []string{"bag"},
+		Templates: []WordTemplate{{Operation: Addition, Grade: 1, Text: "{{plural .Item}} {{.A}} {{.B}}"}},
+	}
+	if _, err := NewWordProblemGenerator(library, 3); err == nil {
+		t.Error("Expected an error for a template that calls plural wrongly")
+	}
+}
+
+func TestPluralize(t *testing.T) {
+	tests := []struct {
+		noun  string
+		count int
+		want  string
+	}{
+		{"sticker", 1, "sticker"},
+		{"sticker", 2, "stickers"},
+		{"sticker", 0, "stickers"},
+		{"strawberry", 3, "strawberries"},
+		{"toy", 3, "toys"},
+		{"peach", 2, "peaches"},
+		{"box", 2, "boxes"},
+		{"leaf", 2, "leaves"},
+		{"child", 2, "children"},
+		{"box of raisins", 4, "boxes of raisins"},
+		{"toy bus", 4, "toy buses"},
+	}
+
+	for _, tt := range tests {
+		if got := Pluralize(tt.noun, tt.count); got != tt.want {
+			t.Errorf("Pluralize(%q, %d) = %q, want %q", tt.noun, tt.count, got, tt.want)
+		}
+	}
+}
+
+func TestPlaceValueGenerator(t *testing.T) {
+	generator := NewPlaceValueGenerator(1000, 9999)
+
+	if generator.Type() != PlaceValue {
+		t.Errorf("Expected problem type %s, got %s", PlaceValue, generator.Type())
+	}
+
+	for i := 0; i < 100; i++ {
+		problem := generator.Generate()
+
+		number, place := problem.Operands[0], problem.Operands[1]
+		if number < 1000 || number > 9999 {
+			t.Errorf("Number out of range: %d", number)
+		}
+		if expected := number / place % 10; problem.Answer != expected {
+			t.Errorf("Problem: %s, expected answer %d, got %d", problem.Question, expected, problem.Answer)
+		}
+		if !strings.Contains(problem.Question, FormatThousands(number)) {
+			t.Errorf("Expected number with commas in: %s", problem.Question)
+		}
+	}
+}
+
+func TestPlaceGeneratorsClampRange(t *testing.T) {
+	// Numbers past the millions have places without names
+	generators := []Generator{
+		NewPlaceValueGenerator(10_000_000, 99_999_999),
+		NewRoundingGenerator(10_000_000, 99_999_999),
+	}
+	for _, generator := range generators {
+		for i := 0; i < 20; i++ {
+			if number := generator.Generate().Operands[0]; number > maxPlaceNumber {
+				t.Errorf("%s: number %d out of range", generator.Type(), number)
+			}
+		}
+	}
+}
+
+func TestRoundingGenerator(t *testing.T) {
+	generator := NewRoundingGenerator(10, 999)
+
+	if generator.Type() != Rounding {
+		t.Errorf("Expected problem type %s, got %s", Rounding, generator.Type())
+	}
+
+	for i := 0; i < 100; i++ {
+		problem := generator.Generate()
+
+		number, place := problem.Operands[0], problem.Operands[1]
+		if place != 10 && place != 100 {
+			t.Errorf("Unexpected rounding place %d in: %s", place, problem.Question)
+		}
+		if problem.Answer%place != 0 {
+			t.Errorf("Answer %d is not a multiple of %d", problem.Answer, place)
+		}
+
+		// The rounded value must be within half a place of the number
+		diff := number - problem.Answer
+		if diff < -place/2 || diff >= place/2 {
+			t.Errorf("Problem: %s, answer %d is not the nearest %d", problem.Question, problem.Answer, place)
+		}
+	}
+
+	if got := roundTo(347, 10); got != 350 {
+		t.Errorf("roundTo(347, 10) = %d, want 350", got)
+	}
+	if got := roundTo(350, 100); got != 400 {
+		t.Errorf("roundTo(350, 100) = %d, want 400", got)
+	}
+}
+
+func TestEstimationGenerator(t *testing.T) {
+	generator := NewEstimationGenerator(10, 99)
+
+	if generator.Type() != Estimation {
+		t.Errorf("Expected problem type %s, got %s", Estimation, generator.Type())
+	}
+
+	for i := 0; i < 100; i++ {
+		problem := generator.Generate()
+
+		if problem.Tolerance != 5 {
+			t.Errorf("Expected tolerance 5, got %d", problem.Tolerance)
+		}
+
+		// The estimate is within a rounding place of the exact answer
+		exact := problem.Operands[0] + problem.Operands[1]
+		if strings.Contains(problem.Question, " - ") {
+			exact = problem.Operands[0] - problem.Operands[1]
+		}
+		if diff := exact - problem.Answer; diff < -10 || diff > 10 {
+			t.Errorf("Problem: %s, estimate %d is too far from %d", problem.Question, problem.Answer, exact)
+		}
+
+		// Only answers that round to the estimate are accepted
+		if !problem.IsCorrect(problem.Answer-5) || !problem.IsCorrect(problem.Answer+4) {
+			t.Errorf("Problem: %s, answer rounding to %d not accepted", problem.Question, problem.Answer)
+		}
+		if problem.IsCorrect(problem.Answer+6) || problem.IsCorrect(problem.Answer-10) {
+			t.Errorf("Problem: %s, answer outside tolerance accepted", problem.Question)
+		}
+	}
+
+	// Numbers are limited like rounding numbers
+	generator = NewEstimationGenerator(-5, 50000000)
+	for i := 0; i < 100; i++ {
+		for _, n := range generator.Generate().Operands {
+			if n < 0 || n > maxPlaceNumber {
+				t.Fatalf("Expected numbers from 0 to %d, got %d", maxPlaceNumber, n)
+			}
+		}
+	}
+}
+
+func TestFormatThousands(t *testing.T) {
+	tests := map[int]string{
+		7:       "7",
+		382:     "382",
+		5382:    "5,382",
+		1000000: "1,000,000",
+		-45000:  "-45,000",
+	}
+
+	for n, want := range tests {
+		if got := FormatThousands(n); got != want {
+			t.Errorf("FormatThousands(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestComparisonGenerator(t *testing.T) {
+	generator := NewComparisonGenerator(2)
+
+	if generator.Type() != Comparison {
+		t.Errorf("Expected problem type %s, got %s", Comparison, generator.Type())
+	}
+
+	sawEqual := false
+	for i := 0; i < 100; i++ {
+		problem := generator.Generate()
+
+		if problem.Kind != ComparisonAnswer {
+			t.Errorf("Expected answer kind %s, got %s", ComparisonAnswer, problem.Kind)
+		}
+
+		var num1, num2, target int
+		var operator string
+		n, err := fmt.Sscanf(problem.Question, "%d %s %d __ %d", &num1, &operator, &num2, &target)
+		if err != nil || n != 4 {
+			t.Errorf("Failed to parse problem: %s", problem.Question)
+			continue
+		}
+
+		value := num1 + num2
+		if operator == "-" {
+			value = num1 - num2
+		}
+
+		// The symbol the user would type must be accepted
+		symbol := "="
+		if value < target {
+			symbol = "<"
+		} else if value > target {
+			symbol = ">"
+		}
+		if symbol == "=" {
+			sawEqual = true
+		}
+
+		answer, err := problem.ParseAnswer(symbol)
+		if err != nil || !problem.IsCorrect(answer) {
+			t.Errorf("Problem: %s, expected %s to be correct", problem.Question, symbol)
+		}
+		if problem.FormatAnswer() != symbol {
+			t.Errorf("Problem: %s, expected answer %s, got %s", problem.Question, symbol, problem.FormatAnswer())
+		}
+	}
+
+	if !sawEqual {
+		t.Errorf("Expected some problems with equal sides")
+	}
+}
+
+func TestParseComparisonAnswer(t *testing.T) {
+	problem := Problem{Kind: ComparisonAnswer}
+
+	valid := map[string]int{"<": -1, " > ": 1, "=": 0, "Less than": -1, "greater": 1}
+	for input, want := range valid {
+		if got, err := problem.ParseAnswer(input); err != nil || got != want {
+			t.Errorf("ParseAnswer(%q) = %d, %v; want %d", input, got, err, want)
+		}
+	}
+
+	for _, input := range []string{"", "12", "<>", "maybe"} {
+		if _, err := problem.ParseAnswer(input); err == nil {
+			t.Errorf("ParseAnswer(%q) expected an error", input)
+		}
+	}
+}
+
+func TestTimeGenerator(t *testing.T) {
+	generator := NewTimeGenerator(5)
+
+	if generator.Type() != Time {
+		t.Errorf("Expected problem type %s, got %s", Time, generator.Type())
+	}
+
+	for i := 0; i < 100; i++ {
+		problem := generator.Generate()
+
+		if problem.Kind != TimeAnswer {
+			t.Errorf("Expected answer kind %s, got %s", TimeAnswer, problem.Kind)
+		}
+
+		hour, minute := problem.Operands[0], problem.Operands[1]
+		if hour < 1 || hour > 12 || minute%5 != 0 {
+			t.Errorf("Unexpected clock time %d:%02d", hour, minute)
+		}
+
+		expected := hour%12*60 + minute
+		if len(problem.Operands) == 3 {
+			expected = (expected + problem.Operands[2]) % (12 * 60)
+		}
+		if problem.Answer != expected {
+			t.Errorf("Problem: %s, expected answer %s, got %s",
+				problem.Question, FormatClockTime(expected), problem.FormatAnswer())
+		}
+
+		// The displayed answer must be accepted as input
+		answer, err := problem.ParseAnswer(problem.FormatAnswer())
+		if err != nil || !problem.IsCorrect(answer) {
+			t.Errorf("Answer %s did not round-trip: %v", problem.FormatAnswer(), err)
+		}
+	}
+}
+
+func TestParseClockTime(t *testing.T) {
+	valid := map[string]int{"12:00": 0, "3:45": 225, "03:05": 185, " 11:59 ": 719}
+	for input, want := range valid {
+		if got, err := ParseClockTime(input); err != nil || got != want {
+			t.Errorf("ParseClockTime(%q) = %d, %v; want %d", input, got, err, want)
+		}
+	}
+
+	for _, input := range []string{"", "3", "3:5", "13:00", "0:30", "3:60", "3:45pm", "three"} {
+		if _, err := ParseClockTime(input); err == nil {
+			t.Errorf("ParseClockTime(%q) expected an error", input)
+		}
+	}
+}
+
+func TestMeasurementGenerator(t *testing.T) {
+	generator := NewMeasurementGenerator(10, Metric)
+
+	if generator.Type() != Measurement {
+		t.Errorf("Expected problem type %s, got %s", Measurement, generator.Type())
+	}
+
+	for i := 0; i < 100; i++ {
+		problem := generator.Generate()
+
+		unit, ok := LookupUnit(problem.Unit)
+		if !ok {
+			t.Errorf("Problem: %s has unknown unit %q", problem.Question, problem.Unit)
+			continue
+		}
+		if unit.System == Customary {
+			t.Errorf("Customary unit in metric problem: %s", problem.Prompt())
+		}
+		if !strings.HasSuffix(problem.Prompt(), unit.Plural) {
+			t.Errorf("Prompt doesn't state the answer unit: %s", problem.Prompt())
+		}
+
+		// Converting down multiplies by the factor, converting up divides
+		amount, factor := problem.Operands[0], problem.Operands[1]
+		if problem.Answer != amount*factor && problem.Answer*factor != amount {
+			t.Errorf("Problem: %s, wrong answer %d", problem.Prompt(), problem.Answer)
+		}
+	}
+}
+
+func TestParseAnswerWithUnit(t *testing.T) {
+	problem := Problem{Question: "3 feet", Answer: 36, Unit: "inch"}
+
+	for _, input := range []string{"36", "36 in", "36in", "36 inches", "36 Inches"} {
+		if got, err := problem.ParseAnswer(input); err != nil || got != 36 {
+			t.Errorf("ParseAnswer(%q) = %d, %v; want 36", input, got, err)
+		}
+	}
+
+	for _, input := range []string{"36 ft", "36 cm", "inches"} {
+		if _, err := problem.ParseAnswer(input); err == nil {
+			t.Errorf("ParseAnswer(%q) expected an error", input)
+		}
+	}
+
+	if got := problem.FormatAnswer(); got != "36 inches" {
+		t.Errorf("FormatAnswer() = %q, want %q", got, "36 inches")
+	}
+	if got := (Problem{Answer: 1, Unit: "foot"}).FormatAnswer(); got != "1 foot" {
+		t.Errorf("FormatAnswer() = %q, want %q", got, "1 foot")
+	}
+}
+
+func TestGeometryGenerator(t *testing.T) {
+	generator := NewGeometryGenerator(12)
+
+	if generator.Type() != Geometry {
+		t.Errorf("Expected problem type %s, got %s", Geometry, generator.Type())
+	}
+
+	for i := 0; i < 100; i++ {
+		problem := generator.Generate()
+
+		if problem.Figure == "" {
+			t.Errorf("Expected a figure for: %s", problem.Question)
+		}
+
+		// Every side length must be labeled on the drawing
+		width, height := problem.Operands[0], problem.Operands[1]
+		area, perimeter := width*height, 2*(width+height)
+		labels := []int{width, height}
+		if len(problem.Operands) == 4 {
+			cutWidth, cutHeight := problem.Operands[2], problem.Operands[3]
+			area -= cutWidth * cutHeight
+			labels = append(labels, cutWidth, cutHeight, width-cutWidth, height-cutHeight)
+		}
+		for _, side := range labels {
+			if !strings.Contains(problem.Figure, fmt.Sprintf(" %d ", side)) {
+				t.Errorf("Side %d not labeled in figure:\n%s", side, problem.Figure)
+			}
+		}
+
+		expected := perimeter
+		if strings.Contains(problem.Question, "area") {
+			expected = area
+			if !strings.HasPrefix(problem.Unit, "square ") {
+				t.Errorf("Expected a square unit for area, got %q", problem.Unit)
+			}
+		}
+		if problem.Answer != expected {
+			t.Errorf("Problem: %s, expected answer %d, got %d", problem.Question, expected, problem.Answer)
+		}
+	}
+}
+
+func TestSequenceGenerator(t *testing.T) {
+	generator := NewSequenceGenerator(5)
+
+	if generator.Type() != Sequence {
+		t.Errorf("Expected problem type %s, got %s", Sequence, generator.Type())
+	}
+
+	for i := 0; i < 100; i++ {
+		problem := generator.Generate()
+		terms := problem.Operands
+
+		if len(terms) != 5 {
+			t.Errorf("Expected 5 terms, got %v", terms)
+			continue
+		}
+		if strings.Count(problem.Question, "__") != 1 {
+			t.Errorf("Expected exactly one missing term: %s", problem.Question)
+		}
+
+		// Gaps are either constant or grow by one each step
+		first := terms[1] - terms[0]
+		second := terms[2] - terms[1]
+		for j := 2; j < len(terms); j++ {
+			gap := terms[j] - terms[j-1]
+			if gap != first && gap != first+(j-1)*(second-first) {
+				t.Errorf("Terms don't follow a pattern: %v", terms)
+			}
+		}
+		if second-first != 0 && second-first != 1 {
+			t.Errorf("Unexpected pattern: %v", terms)
+		}
+
+		// The shown terms plus the answer make up the full sequence
+		expected := strings.Replace(problem.Question, "__", fmt.Sprintf("%d", problem.Answer), 1)
+		for _, term := range terms {
+			if !strings.Contains(expected, fmt.Sprintf("%d", term)) {
+				t.Errorf("Term %d missing from %s", term, expected)
+			}
+		}
+	}
+}
+
+func TestSequenceGeneratorSeed(t *testing.T) {
+	first := NewSequenceGenerator(6)
+	second := NewSequenceGenerator(6)
+
+	var seeder Seeder = first
+	seeder.Seed(42)
+	second.Seed(42)
+
+	// The same seed must give the same problems
+	for i := 0; i < 20; i++ {
+		a, b := first.Generate(), second.Generate()
+		if a.Question != b.Question || a.Answer != b.Answer {
+			t.Errorf("Seeded generators differ: %q vs %q", a.Question, b.Question)
+		}
+	}
+}
+
+func TestLongMultiplicationGenerator(t *testing.T) {
+	generator := NewLongMultiplicationGenerator(3, 2, true)
+
+	if generator.Type() != LongMultiplication {
+		t.Errorf("Expected problem type %s, got %s", LongMultiplication, generator.Type())
+	}
+
+	for i := 0; i < 100; i++ {
+		problem := generator.Generate()
+
+		top, bottom := problem.Operands[0], problem.Operands[1]
+		if top < 100 || top > 999 || bottom < 10 || bottom > 99 {
+			t.Errorf("Operands out of range: %s", problem.Question)
+		}
+		if problem.Answer != top*bottom {
+			t.Errorf("Problem: %s, expected answer %d, got %d", problem.Question, top*bottom, problem.Answer)
+		}
+
+		// One partial product per digit, adding up to the answer
+		if len(problem.Steps) != 2 {
+			t.Errorf("Expected 2 steps, got %d", len(problem.Steps))
+			continue
+		}
+		sum := 0
+		for _, step := range problem.Steps {
+			sum += step.Answer
+		}
+		if sum != problem.Answer {
+			t.Errorf("Problem: %s, partial products add up to %d", problem.Question, sum)
+		}
+	}
+
+	// Forgetting the placeholder zero gets specific feedback
+	steps := multiplicationSteps(347, 26)
+	if steps[0].Answer != 2082 || steps[1].Answer != 6940 {
+		t.Fatalf("Unexpected partial products: %d, %d", steps[0].Answer, steps[1].Answer)
+	}
+	if feedback := steps[1].Explain(694); !strings.Contains(feedback, "placeholder zero") {
+		t.Errorf("Expected placeholder zero feedback, got: %s", feedback)
+	}
+	if feedback := steps[0].Explain(2072); !strings.Contains(feedback, "carry 4") {
+		t.Errorf("Expected carry explanation, got: %s", feedback)
+	}
+
+	if problem := NewLongMultiplicationGenerator(3, 2, false).Generate(); len(problem.Steps) != 0 {
+		t.Errorf("Expected no steps when step mode is off")
+	}
+}
+
+func TestLongDivisionGenerator(t *testing.T) {
+	generator := NewLongDivisionGenerator(4, 9, true)
+
+	if generator.Type() != LongDivision {
+		t.Errorf("Expected problem type %s, got %s", LongDivision, generator.Type())
+	}
+
+	for i := 0; i < 100; i++ {
+		problem := generator.Generate()
+
+		dividend, divisor := problem.Operands[0], problem.Operands[1]
+		if dividend < 1000 || dividend > 9999 {
+			t.Errorf("Dividend out of range: %d", dividend)
+		}
+		if dividend%divisor != 0 || problem.Answer != dividend/divisor {
+			t.Errorf("Problem: %s, expected answer %d, got %d", problem.Question, dividend/divisor, problem.Answer)
+		}
+
+		// The divide steps spell out the quotient and the last remainder is zero
+		quotient := 0
+		for j, step := range problem.Steps {
+			if j%2 == 0 {
+				quotient = quotient*10 + step.Answer
+			}
+		}
+		if quotient != problem.Answer {
+			t.Errorf("Problem: %s, steps give quotient %d", problem.Question, quotient)
+		}
+		if last := problem.Steps[len(problem.Steps)-1]; last.Answer != 0 {
+			t.Errorf("Problem: %s, final remainder %d", problem.Question, last.Answer)
+		}
+	}
+
+	// Guessing too high or too low is explained
+	steps := divisionSteps(2345*7, 7)
+	if feedback := steps[0].Explain(9); !strings.Contains(feedback, "Too many") {
+		t.Errorf("Expected too-many feedback, got: %s", feedback)
+	}
+	if feedback := steps[0].Explain(1); !strings.Contains(feedback, "Too few") {
+		t.Errorf("Expected too-few feedback, got: %s", feedback)
+	}
+}
+
+func TestExponentGenerator(t *testing.T) {
+	generator := NewExponentGenerator(12, 6)
+
+	if generator.Type() != Exponent {
+		t.Errorf("Expected problem type %s, got %s", Exponent, generator.Type())
+	}
+
+	for i := 0; i < 100; i++ {
+		problem := generator.Generate()
+
+		if strings.HasPrefix(problem.Question, "√") {
+			square := problem.Operands[0]
+			if problem.Answer*problem.Answer != square {
+				t.Errorf("Problem: %s, %d is not the square root", problem.Question, problem.Answer)
+			}
+			continue
+		}
+
+		base, exponent := problem.Operands[0], problem.Operands[1]
+		if problem.Question != fmt.Sprintf("%d%s", base, Superscript(exponent)) {
+			t.Errorf("Unexpected question %s for %d^%d", problem.Question, base, exponent)
+		}
+
+		expected := 1
+		for j := 0; j < exponent; j++ {
+			expected *= base
+		}
+		if problem.Answer != expected {
+			t.Errorf("Problem: %s, expected answer %d, got %d", problem.Question, expected, problem.Answer)
+		}
+		if base == 10 && (exponent < 1 || exponent > 6) {
+			t.Errorf("Power of ten out of range: %s", problem.Question)
+		}
+	}
+
+	if got := Superscript(10); got != "¹⁰" {
+		t.Errorf("Superscript(10) = %q, want %q", got, "¹⁰")
+	}
+}
+
+func TestASCII(t *testing.T) {
+	tests := map[string]string{
+		"7²":             "7^2",
+		"10¹⁰":           "10^10",
+		"√49":            "sqrt(49)",
+		"6 × 7":          "6 x 7",
+		"42 ÷ 6":         "42 / 6",
+		"3 feet = ? ft²": "3 feet = ? ft^2",
+		"plain text":     "plain text",
+	}
+
+	for input, want := range tests {
+		if got := ASCII(input); got != want {
+			t.Errorf("ASCII(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestPercentGenerator(t *testing.T) {
+	for difficulty := 1; difficulty <= 3; difficulty++ {
+		generator := NewPercentGenerator(difficulty)
+
+		if generator.Type() != Percent {
+			t.Errorf("Expected problem type %s, got %s", Percent, generator.Type())
+		}
+
+		for i := 0; i < 200; i++ {
+			problem := generator.Generate()
+			ops := problem.Operands
+
+			switch {
+			case strings.Contains(problem.Question, "% of"):
+				// Whole-number answer that really is the percentage
+				if ops[0]*ops[1] != problem.Answer*100 {
+					t.Errorf("Problem: %s, wrong answer %d", problem.Question, problem.Answer)
+				}
+			case strings.Contains(problem.Question, "percent"):
+				if difficulty == 1 {
+					t.Errorf("Percent change at difficulty 1: %s", problem.Question)
+				}
+				change := ops[1] - ops[0]
+				if change < 0 {
+					change = -change
+				}
+				if change*100 != problem.Answer*ops[0] {
+					t.Errorf("Problem: %s, wrong answer %d", problem.Question, problem.Answer)
+				}
+				if answer, err := problem.ParseAnswer(fmt.Sprintf("%d%%", problem.Answer)); err != nil || answer != problem.Answer {
+					t.Errorf("Expected %d%% to be accepted: %v", problem.Answer, err)
+				}
+			default:
+				// a : b = c : d must be a true proportion
+				if ops[0]*ops[3] != ops[1]*ops[2] {
+					t.Errorf("Problem: %s is not a proportion: %v", problem.Question, ops)
+				}
+				if problem.Answer != ops[1] && problem.Answer != ops[3] {
+					t.Errorf("Problem: %s, wrong answer %d", problem.Question, problem.Answer)
+				}
+			}
+		}
+	}
+}
+
+func TestRegistryDefaults(t *testing.T) {
+	definitions := Definitions()
+	if len(definitions) == 0 {
+		t.Fatal("Expected registered problem types")
+	}
+
+	// Every registered type must build with its defaults and make problems of its type
+	for _, def := range definitions {
+		generator, err := def.Generator(nil)
+		if err != nil {
+			t.Errorf("%s: failed to create generator: %v", def.Type, err)
+			continue
+		}
+		if generator.Type() != def.Type {
+			t.Errorf("%s: generator has type %s", def.Type, generator.Type())
+		}
+		if problem := generator.Generate(); problem.Type != def.Type {
+			t.Errorf("%s: generated problem has type %s", def.Type, problem.Type)
+		}
+
+		for _, s := range def.Settings {
+			if s.Default < s.Min || s.Default > s.Max {
+				t.Errorf("%s: default for %s is out of range", def.Type, s.Key)
+			}
+			if len(s.Choices) > 0 && len(s.Choices) != s.Max-s.Min+1 {
+				t.Errorf("%s: %s has %d choices for %d values", def.Type, s.Key, len(s.Choices), s.Max-s.Min+1)
+			}
+		}
+	}
+}
+
+func TestRegistryConfig(t *testing.T) {
+	def, ok := Lookup(Addition)
+	if !ok {
+		t.Fatal("Addition is not registered")
+	}
+
+	generator, err := def.Generator(Config{"maxDigits": 1})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+	for i := 0; i < 50; i++ {
+		if problem := generator.Generate(); problem.Operands[0] > 9 || problem.Operands[1] > 9 {
+			t.Errorf("Expected single-digit operands, got: %s", problem.Question)
+		}
+	}
+
+	if _, err := def.Generator(Config{"maxDigits": 99}); err == nil {
+		t.Errorf("Expected an error for an out-of-range setting")
+	}
+	if _, err := def.Generator(Config{"colour": 1}); err == nil {
+		t.Errorf("Expected an error for an unknown setting")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected a panic when registering a type twice")
+		}
+	}()
+	Register(def)
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	dir := t.TempDir()
+
+	configs, err := LoadConfigFile(filepath.Join(dir, "missing.json"))
+	if err != nil || len(configs) != 0 {
+		t.Errorf("Expected no configs for a missing file, got %v, %v", configs, err)
+	}
+
+	path := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(path, []byte(`{"multiplication": {"maxFactor": 9}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	configs, err = LoadConfigFile(path)
+	if err != nil || configs[Multiplication]["maxFactor"] != 9 {
+		t.Errorf("Unexpected configs %v, %v", configs, err)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"juggling": {"balls": 3}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadConfigFile(path); err == nil {
+		t.Errorf("Expected an error for an unknown problem type")
+	}
+}
+
+func TestCustomGenerator(t *testing.T) {
+	spec := CustomSpec{
+		Type:        "tens-subtraction",
+		Name:        "Subtracting Tens",
+		Operator:    "-",
+		Operands:    []OperandRange{{Min: 10, Max: 90, Step: 10}, {Min: 1, Max: 50}},
+		Constraints: []string{"answer >= 0", "answer <= 50"},
+	}
+
+	generator, err := NewCustomGenerator(spec)
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+	if generator.Type() != "custom-tens-subtraction" || generator.Name() != "Subtracting Tens" {
+		t.Errorf("Unexpected type %s and name %s", generator.Type(), generator.Name())
+	}
+
+	for i := 0; i < 100; i++ {
+		problem := generator.Generate()
+		a, b := problem.Operands[0], problem.Operands[1]
+
+		if a%10 != 0 || a < 10 || a > 90 || b < 1 || b > 50 {
+			t.Errorf("Operands out of range: %s", problem.Question)
+		}
+		if problem.Answer != a-b || problem.Answer < 0 || problem.Answer > 50 {
+			t.Errorf("Problem: %s breaks constraints with answer %d", problem.Question, problem.Answer)
+		}
+		if problem.Question != fmt.Sprintf("%d - %d", a, b) {
+			t.Errorf("Unexpected question: %s", problem.Question)
+		}
+	}
+}
+
+func TestCustomGeneratorDivisionAndTemplate(t *testing.T) {
+	generator, err := NewCustomGenerator(CustomSpec{
+		Type:     "sharing",
+		Name:     "Sharing",
+		Operator: "/",
+		Operands: []OperandRange{{Min: 1, Max: 100}, {Min: 2, Max: 10}},
+		Question: "Share {{.A}} marbles between {{.B}} friends. How many each?",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+
+	for i := 0; i < 100; i++ {
+		problem := generator.Generate()
+		a, b := problem.Operands[0], problem.Operands[1]
+
+		// Division is always exact
+		if a%b != 0 || problem.Answer*b != a {
+			t.Errorf("Problem: %s, uneven division %d ÷ %d", problem.Question, a, b)
+		}
+		if !problem.Statement || !strings.HasPrefix(problem.Question, fmt.Sprintf("Share %d marbles", a)) {
+			t.Errorf("Template not used: %s", problem.Question)
+		}
+	}
+}
+
+func TestCustomGeneratorRareConstraints(t *testing.T) {
+	// Only one pair in ten thousand meets the constraints, so random drawing
+	// would often give up
+	generator, err := NewCustomGenerator(CustomSpec{
+		Type:        "rare",
+		Name:        "Rare",
+		Operator:    "/",
+		Operands:    []OperandRange{{Min: 0, Max: 99}, {Min: 0, Max: 99}},
+		Constraints: []string{"a = 98", "b = 49"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+
+	for i := 0; i < 100; i++ {
+		if problem := generator.Generate(); problem.Question != "98 ÷ 49" || problem.Answer != 2 {
+			t.Fatalf("Expected 98 ÷ 49 = 2, got %s = %d", problem.Question, problem.Answer)
+		}
+	}
+
+	// Ranges too big to list still give problems that meet the constraints
+	generator, err = NewCustomGenerator(CustomSpec{
+		Type:        "big",
+		Name:        "Big",
+		Operator:    "+",
+		Operands:    []OperandRange{{Min: 1, Max: 100000}, {Min: 1, Max: 100000}},
+		Constraints: []string{"answer <= 20000"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+	for i := 0; i < 100; i++ {
+		if problem := generator.Generate(); problem.Answer > 20000 {
+			t.Fatalf("Problem %s breaks the constraints", problem.Question)
+		}
+	}
+}
+
+func TestCustomGeneratorRanges(t *testing.T) {
+	// Counting the pairs of huge ranges mustn't overflow into a small number
+	// that would have every pair listed
+	huge := OperandRange{Min: 0, Max: 1 << 62}
+	if got := candidates(huge); got != maxCustomPairs+1 {
+		t.Errorf("Expected a huge range to count as %d, got %d", maxCustomPairs+1, got)
+	}
+	generator, err := NewCustomGenerator(CustomSpec{
+		Type:     "huge",
+		Name:     "Huge",
+		Operator: "+",
+		Operands: []OperandRange{huge, huge},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+	if generator.pairs != nil {
+		t.Errorf("Expected huge ranges to be drawn at random, got %d pairs listed", len(generator.pairs))
+	}
+
+	// Multiples of a step start at the lowest one in range, below zero too
+	stepped := OperandRange{Min: -7, Max: 7, Step: 3}
+	if got, want := values(stepped), []int{-6, -3, 0, 3, 6}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if got := candidates(stepped); got != 5 {
+		t.Errorf("Expected 5 multiples of 3, got %d", got)
+	}
+}
+
+func TestCustomGeneratorErrors(t *testing.T) {
+	valid := CustomSpec{
+		Type:     "valid",
+		Name:     "Valid",
+		Operator: "+",
+		Operands: []OperandRange{{Min: 1, Max: 9}, {Min: 1, Max: 9}},
+	}
+
+	tests := map[string]func(s *CustomSpec){
+		"missing name":      func(s *CustomSpec) { s.Name = "" },
+		"unsafe type":       func(s *CustomSpec) { s.Type = "../oops" },
+		"unknown operator":  func(s *CustomSpec) { s.Operator = "^" },
+		"one operand":       func(s *CustomSpec) { s.Operands = s.Operands[:1] },
+		"backwards range":   func(s *CustomSpec) { s.Operands = []OperandRange{{Min: 9, Max: 1}, {Min: 1, Max: 9}} },
+		"bad constraint":    func(s *CustomSpec) { s.Constraints = []string{"answer is small"} },
+		"impossible":        func(s *CustomSpec) { s.Constraints = []string{"answer > 100"} },
+		"no multiples":      func(s *CustomSpec) { s.Operands[0] = OperandRange{Min: 1, Max: 9, Step: 10} },
+		"bad question text": func(s *CustomSpec) { s.Question = "{{.A" },
+		"unknown field":     func(s *CustomSpec) { s.Question = "{{.C}} apples" },
+	}
+
+	for name, breakSpec := range tests {
+		spec := valid
+		spec.Operands = append([]OperandRange(nil), valid.Operands...)
+		breakSpec(&spec)
+		if _, err := NewCustomGenerator(spec); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+// keepRegistry restores the registry when the test ends, so the types it
+// registers don't show up in other tests
+func keepRegistry(t *testing.T) {
+	saved := Definitions()
+	t.Cleanup(func() { definitions = saved })
+}
+
+func TestRegisterCustomDir(t *testing.T) {
+	keepRegistry(t)
+	dir := t.TempDir()
+
+	if err := RegisterCustomDir(filepath.Join(dir, "missing")); err != nil {
+		t.Errorf("Expected no error for a missing directory, got %v", err)
+	}
+
+	spec := `{"type": "doubles", "name": "Doubles", "operator": "+",
+		"operands": [{"min": 1, "max": 10}, {"min": 1, "max": 10}], "constraints": ["a = b"]}`
+	if err := os.WriteFile(filepath.Join(dir, "doubles.json"), []byte(spec), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterCustomDir(dir); err != nil {
+		t.Fatalf("Failed to register definitions: %v", err)
+	}
+
+	// Custom types are prefixed so they can't clash with the game's own
+	// types and files
+	def, ok := Lookup("custom-doubles")
+	if !ok {
+		t.Fatal("Custom type was not registered")
+	}
+	generator, err := def.Generator(nil)
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+	if problem := generator.Generate(); problem.Operands[0] != problem.Operands[1] {
+		t.Errorf("Expected a doubles problem, got: %s", problem.Question)
+	}
+
+	// Registering the same file again clashes with the existing type
+	if err := RegisterCustomDir(dir); err == nil {
+		t.Errorf("Expected an error for a duplicate type")
+	}
+}
+
+func TestLoadQuiz(t *testing.T) {
+	dir := t.TempDir()
+
+	csvQuiz := "question,answer\n7 × 8,56\nHow many legs do 3 spiders have?,24\n4.5 + 2.25 = ?,6.75\n"
+	if err := os.WriteFile(filepath.Join(dir, "Week 3.csv"), []byte(csvQuiz), 0644); err != nil {
+		t.Fatal(err)
+	}
+	quiz, err := LoadQuiz(filepath.Join(dir, "Week 3.csv"))
+	if err != nil {
+		t.Fatalf("Failed to load CSV quiz: %v", err)
+	}
+	if quiz.Name != "Week 3" || quiz.Type() != "quiz-week-3" {
+		t.Errorf("Expected quiz Week 3 (quiz-week-3), got %s (%s)", quiz.Name, quiz.Type())
+	}
+
+	tests := []struct {
+		prompt string
+		input  string
+	}{
+		{"7 × 8 = ?", "56"},
+		{"How many legs do 3 spiders have?", "24"},
+		{"4.5 + 2.25 = ?", "6.75"},
+	}
+	if len(quiz.Problems) != len(tests) {
+		t.Fatalf("Expected %d problems, got %d", len(tests), len(quiz.Problems))
+	}
+	for i, test := range tests {
+		problem := quiz.Problems[i]
+		if problem.Prompt() != test.prompt {
+			t.Errorf("Problem %d: expected prompt %q, got %q", i+1, test.prompt, problem.Prompt())
+		}
+		answer, err := problem.ParseAnswer(test.input)
+		if err != nil || !problem.IsCorrect(answer) {
+			t.Errorf("Problem %d: expected %q to be correct", i+1, test.input)
+		}
+	}
+
+	jsonQuiz := `{"name": "Division Review", "problems": [
+		{"a": 72, "operator": "÷", "b": 8}, {"a": 12, "operator": "-", "b": 20}]}`
+	if err := os.WriteFile(filepath.Join(dir, "review.json"), []byte(jsonQuiz), 0644); err != nil {
+		t.Fatal(err)
+	}
+	quiz, err = LoadQuiz(filepath.Join(dir, "review.json"))
+	if err != nil {
+		t.Fatalf("Failed to load JSON quiz: %v", err)
+	}
+	if quiz.Problems[0].Question != "72 ÷ 8" || quiz.Problems[0].Answer != 9 {
+		t.Errorf("Expected 72 ÷ 8 = 9, got %s = %d", quiz.Problems[0].Question, quiz.Problems[0].Answer)
+	}
+	if quiz.Problems[1].Answer != -8 {
+		t.Errorf("Expected 12 - 20 = -8, got %d", quiz.Problems[1].Answer)
+	}
+
+	// Answers can be JSON numbers or strings
+	jsonQuiz = `{"problems": [{"question": "7 × 8", "answer": 56}, {"question": "4.5 + 2.25", "answer": 6.75},
+		{"question": "How many legs do 3 spiders have?", "answer": "24"}]}`
+	if err := os.WriteFile(filepath.Join(dir, "numbers.json"), []byte(jsonQuiz), 0644); err != nil {
+		t.Fatal(err)
+	}
+	quiz, err = LoadQuiz(filepath.Join(dir, "numbers.json"))
+	if err != nil {
+		t.Fatalf("Failed to load JSON quiz with numeric answers: %v", err)
+	}
+	for i, want := range []struct{ answer, decimals int }{{56, 0}, {675, 2}, {24, 0}} {
+		if problem := quiz.Problems[i]; problem.Answer != want.answer || problem.Decimals != want.decimals {
+			t.Errorf("Problem %d: expected answer %d with %d decimals, got %d with %d",
+				i+1, want.answer, want.decimals, problem.Answer, problem.Decimals)
+		}
+	}
+
+	// Invalid quizzes are rejected
+	invalid := map[string]string{
+		"uneven.json":  `{"problems": [{"a": 7, "operator": "÷", "b": 2}]}`,
+		"object.json":  `{"problems": [{"question": "1 + 1", "answer": {"value": 2}}]}`,
+		"empty.json":   `{"problems": []}`,
+		"answer.csv":   "1 + 1,2\n3 + 4,seven\n",
+		"columns.csv":  "1,2,3,4\n",
+		"operator.csv": "3,%,4\n",
+	}
+	for name, content := range invalid {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadQuiz(filepath.Join(dir, name)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestQuizGenerator(t *testing.T) {
+	quiz := &Quiz{Name: "Doubles"}
+	for i := 1; i <= 10; i++ {
+		quiz.Problems = append(quiz.Problems, Problem{Question: fmt.Sprintf("%d + %d", i, i), Answer: 2 * i})
+	}
+
+	// In order, starting over at the end
+	generator := NewQuizGenerator(quiz, false)
+	for round := 0; round < 2; round++ {
+		for i := range quiz.Problems {
+			if problem := generator.Generate(); problem.Answer != quiz.Problems[i].Answer {
+				t.Errorf("Expected problem %d in order, got %s", i+1, problem.Question)
+			}
+		}
+	}
+
+	// Shuffled, each problem once per pass
+	generator = NewQuizGenerator(quiz, true)
+	seen := make(map[int]bool)
+	for i := 0; i < generator.Len(); i++ {
+		seen[generator.Generate().Answer] = true
+	}
+	if len(seen) != len(quiz.Problems) {
+		t.Errorf("Expected every problem once, got %d distinct", len(seen))
+	}
+}
+
+func TestSamplerNoRepeats(t *testing.T) {
+	sampler := NewSampler(NewMultiplicationGenerator(12), SamplingPolicy{NoRepeats: true, SameTurnarounds: true})
+
+	// 78 distinct facts up to 12 × 12 when turnarounds count as the same
+	seen := make(map[[2]int]bool)
+	for i := 0; i < 78; i++ {
+		problem := sampler.Generate()
+		a, b := problem.Operands[0], problem.Operands[1]
+		if a > b {
+			a, b = b, a
+		}
+		if seen[[2]int{a, b}] {
+			t.Fatalf("Problem %d repeated a fact: %s", i+1, problem.Question)
+		}
+		seen[[2]int{a, b}] = true
+	}
+
+	// Generators that can't be enumerated are redrawn instead
+	sampler = NewSampler(NewAdditionGenerator(2), SamplingPolicy{NoRepeats: true})
+	questions := make(map[string]bool)
+	for i := 0; i < 20; i++ {
+		problem := sampler.Generate()
+		if questions[problem.Question] {
+			t.Errorf("Repeated problem: %s", problem.Question)
+		}
+		questions[problem.Question] = true
+	}
+}
+
+func TestSamplerKey(t *testing.T) {
+	sampler := NewSampler(NewMultiplicationGenerator(12), SamplingPolicy{NoRepeats: true, SameTurnarounds: true})
+
+	tests := []struct {
+		a, b Problem
+		same bool
+	}{
+		{multiplicationFact(3, 4), multiplicationFact(4, 3), true},
+		{
+			Problem{Question: "3 + 4", Type: Integers, Operands: []int{3, 4}},
+			Problem{Question: "4 + 3", Type: Integers, Operands: []int{4, 3}},
+			true,
+		},
+		{
+			Problem{Question: "3 + 4", Type: Integers, Operands: []int{3, 4}},
+			Problem{Question: "3 × 4", Type: Integers, Operands: []int{3, 4}},
+			false,
+		},
+		{
+			Problem{Question: "9 - 4", Type: Integers, Operands: []int{9, 4}},
+			Problem{Question: "4 - 9", Type: Integers, Operands: []int{4, 9}},
+			false,
+		},
+		{
+			Problem{Question: "2³", Type: Exponent, Operands: []int{2, 3}},
+			Problem{Question: "3²", Type: Exponent, Operands: []int{3, 2}},
+			false,
+		},
+		{
+			Problem{Question: "What is the area?", Type: Geometry, Operands: []int{3, 4}},
+			Problem{Question: "What is the perimeter?", Type: Geometry, Operands: []int{3, 4}},
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		if same := sampler.key(tt.a) == sampler.key(tt.b); same != tt.same {
+			t.Errorf("%s and %s: expected same %v, got %v", tt.a.Question, tt.b.Question, tt.same, same)
+		}
+	}
+
+	// Without SameTurnarounds, 3 × 4 and 4 × 3 are different problems
+	sampler = NewSampler(NewMultiplicationGenerator(12), SamplingPolicy{NoRepeats: true})
+	if sampler.key(multiplicationFact(3, 4)) == sampler.key(multiplicationFact(4, 3)) {
+		t.Error("Expected turnarounds to differ when SameTurnarounds is off")
+	}
+}
+
+func TestSamplerBalanced(t *testing.T) {
+	sampler := NewSampler(NewDivisionGenerator(10), SamplingPolicy{NoRepeats: true, Balanced: true})
+
+	// Two problems from every divisor's row in the first 20
+	rows := make(map[int]int)
+	for i := 0; i < 20; i++ {
+		problem := sampler.Generate()
+		rows[problem.Operands[1]]++
+	}
+	for divisor := 1; divisor <= 10; divisor++ {
+		if rows[divisor] != 2 {
+			t.Errorf("Expected 2 problems dividing by %d, got %d", divisor, rows[divisor])
+		}
+	}
+}
+
+func TestSamplerSeed(t *testing.T) {
+	first := NewSampler(NewMultiplicationGenerator(12), SamplingPolicy{NoRepeats: true, Balanced: true})
+	second := NewSampler(NewMultiplicationGenerator(12), SamplingPolicy{NoRepeats: true, Balanced: true})
+	first.Seed(7)
+	second.Seed(7)
+
+	for i := 0; i < 20; i++ {
+		if a, b := first.Generate(), second.Generate(); a.Question != b.Question {
+			t.Fatalf("Problem %d differs with the same seed: %s vs %s", i+1, a.Question, b.Question)
+		}
+	}
+}
+
+func TestParseTables(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"6,7,8", "6, 7, 8"},
+		{"2-5, 10", "2, 3, 4, 5, 10"},
+		{"9 9 3", "3, 9"},
+		{"", "all"},
+		{"all", "all"},
+	}
+
+	for _, test := range tests {
+		tables, err := ParseTables(test.input)
+		if err != nil {
+			t.Errorf("ParseTables(%q) returned error: %v", test.input, err)
+			continue
+		}
+		if got := FormatTables(tables); got != test.expected {
+			t.Errorf("ParseTables(%q) = %s, expected %s", test.input, got, test.expected)
+		}
+		if got := FormatTables(MaskTables(TablesMask(tables))); got != test.expected {
+			t.Errorf("Mask round trip of %q = %s, expected %s", test.input, got, test.expected)
+		}
+	}
+
+	for _, input := range []string{"0", "21", "8-6", "seven"} {
+		if _, err := ParseTables(input); err == nil {
+			t.Errorf("ParseTables(%q) expected an error", input)
+		}
+	}
+}
+
+func TestTablesGenerators(t *testing.T) {
+	tables := []int{6, 7, 8}
+
+	multiplication, err := NewMultiplicationTablesGenerator(12, tables)
+	if err != nil {
+		t.Fatalf("Failed to create multiplication generator: %v", err)
+	}
+	division, err := NewDivisionTablesGenerator(12, tables)
+	if err != nil {
+		t.Fatalf("Failed to create division generator: %v", err)
+	}
+
+	inTables := func(n int) bool { return n >= 6 && n <= 8 }
+	for i := 0; i < 100; i++ {
+		problem := multiplication.Generate()
+		if !inTables(problem.Operands[0]) && !inTables(problem.Operands[1]) {
+			t.Errorf("Expected a fact from the 6s, 7s or 8s, got: %s", problem.Question)
+		}
+
+		problem = division.Generate()
+		if !inTables(problem.Operands[1]) && !inTables(problem.Answer) {
+			t.Errorf("Expected a fact from the 6s, 7s or 8s, got: %s", problem.Question)
+		}
+	}
+
+	if got := FormatTables(multiplication.Tables()); got != "6, 7, 8" {
+		t.Errorf("Expected tables 6, 7, 8, got %s", got)
+	}
+	if rows := division.Enumerate(); len(rows) != 3 || len(rows[0]) != 23 {
+		t.Errorf("Expected 3 rows of 23 facts, got %d rows", len(rows))
+	}
+
+	if _, err := NewMultiplicationTablesGenerator(10, []int{12}); err == nil {
+		t.Errorf("Expected an error for a table beyond the largest factor")
+	}
+}
+
+func TestLoadConfigFileTables(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"multiplication": {"tables": [6, 7, 8]}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	configs, err := LoadConfigFile(path)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if got := FormatTables(MaskTables(configs[Multiplication]["tables"])); got != "6, 7, 8" {
+		t.Errorf("Expected tables 6, 7, 8, got %s", got)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"division": {"tables": [25]}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadConfigFile(path); err == nil {
+		t.Errorf("Expected an error for an unknown table")
+	}
+}
+
+func TestExplain(t *testing.T) {
+	tests := []struct {
+		problem  Problem
+		expected []string
+	}{
+		{multiplicationFact(7, 8), []string{"7 × 4 = 28", "28 × 2 = 56"}},
+		{Problem{Type: Addition, Operands: []int{47, 38}, Answer: 85}, []string{"7 + 8 = 15. Write 5 and carry 1", "4 + 3 + 1 carried = 8"}},
+		{Problem{Type: Addition, Operands: []int{7, 8}, Answer: 15}, []string{"8 + 2 = 10, so split 7 into 2 + 5", "7 + 8 = 10 + 5 = 15"}},
+		{Problem{Type: Subtraction, Operands: []int{502, 47}, Answer: 455}, []string{"borrow from the hundreds to make 12", "tens (now 9 after lending)", "hundreds (now 4 after lending)"}},
+		{divisionFact(8, 7), []string{"8 × ? = 56", "8 × 7 = 56, so 56 ÷ 8 = 7"}},
+		{Problem{Type: Exponent, Operands: []int{49}, Answer: 7}, []string{"7 × 7 = 49"}},
+		{Problem{Type: Exponent, Operands: []int{3, 3}, Answer: 27}, []string{"3 × 3 × 3 = 27"}},
+		{Problem{Type: Rounding, Operands: []int{347, 10}, Answer: 350}, []string{"ones digit of 347 is 7", "round up to 350"}},
+		{Problem{Type: LongMultiplication, Operands: []int{347, 26}, Answer: 9022}, []string{"347 × 6 = 2082", "347 × 20 = 6940", "So 347 × 26 = 9022"}},
+	}
+
+	for _, test := range tests {
+		explanation, ok := Explain(test.problem)
+		if !ok {
+			t.Errorf("No explanation for %v %v", test.problem.Type, test.problem.Operands)
+			continue
+		}
+		text := strings.Join(explanation.Steps, "\n")
+		for _, expected := range test.expected {
+			if !strings.Contains(text, expected) {
+				t.Errorf("Explanation of %v %v missing %q:\n%s", test.problem.Type, test.problem.Operands, expected, text)
+			}
+		}
+		if explanation.Hint == "" {
+			t.Errorf("No hint for %v %v", test.problem.Type, test.problem.Operands)
+		}
+	}
+
+	// Types without an explainer have none
+	if _, ok := Explain(Problem{Type: Sequence, Operands: []int{2, 4, 6}}); ok {
+		t.Errorf("Expected no explanation for a sequence problem")
+	}
+}
+
+func TestExplainGeneratedProblems(t *testing.T) {
+	for _, def := range Definitions() {
+		if def.Explain == nil {
+			continue
+		}
+		generator, err := def.Generator(nil)
+		if err != nil {
+			t.Fatalf("%s: failed to create generator: %v", def.Type, err)
+		}
+
+		for i := 0; i < 200; i++ {
+			problem := generator.Generate()
+			explanation, ok := Explain(problem)
+			if !ok {
+				t.Errorf("%s: no explanation for %s", def.Type, problem.Question)
+				break
+			}
+			last := explanation.Steps[len(explanation.Steps)-1]
+			if !strings.Contains(last, fmt.Sprint(problem.Answer)) && !strings.Contains(last, FormatThousands(problem.Answer)) {
+				t.Errorf("%s: explanation of %s doesn't end with the answer %d: %s", def.Type, problem.Question, problem.Answer, last)
+				break
+			}
+		}
+	}
+}
+
+func TestDefinitionLevel(t *testing.T) {
+	addition, _ := Lookup(Addition)
+	if level := addition.Level(nil); level != 2 {
+		t.Errorf("Expected default addition level 2, got %d", level)
+	}
+	if level := addition.Level(Config{"maxDigits": 6}); level != MaxDifficulty {
+		t.Errorf("Expected level capped at %d, got %d", MaxDifficulty, level)
+	}
+
+	if level := (Definition{}).Level(nil); level != 1 {
+		t.Errorf("Expected unrated types to be level 1, got %d", level)
+	}
+
+	for _, def := range Definitions() {
+		if level := def.Level(nil); level < 1 || level > MaxDifficulty {
+			t.Errorf("%s: default level %d out of range", def.Type, level)
+		}
+	}
+}
+
+func TestDefinitionTiers(t *testing.T) {
+	for _, def := range Definitions() {
+		for i, tier := range def.Tiers {
+			if err := def.Validate(tier.Config); err != nil {
+				t.Errorf("%s: tier %q: %v", def.Type, tier.Name, err)
+			}
+			if i > 0 && def.Level(tier.Config) < def.Level(def.Tiers[i-1].Config) {
+				t.Errorf("%s: tier %q is easier than the one before it", def.Type, tier.Name)
+			}
+		}
+	}
+}
+
+func TestSplitMix64(t *testing.T) {
+	// Reference output for seed 0, which must never change
+	random := &splitMix64{}
+	for _, want := range []uint64{0xe220a8397b1dcdaf, 0x6e789e6aa1b965f4, 0x06c45d188009454f} {
+		if got := random.next(); got != want {
+			t.Errorf("Expected %#x, got %#x", want, got)
+		}
+	}
+}
+
+func TestDailyGenerator(t *testing.T) {
+	day := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	g := NewDailyGenerator(day)
+	if g.Len() != 4*dailyProblemsPerType {
+		t.Fatalf("Expected %d problems, got %d", 4*dailyProblemsPerType, g.Len())
+	}
+
+	// The set for a day never changes, whatever the time of day
+	if got := g.Generate().Question; got != "43 + 58" {
+		t.Errorf("Expected the first problem on 2024-03-01 to be 43 + 58, got %q", got)
+	}
+	later := NewDailyGenerator(day.Add(12 * time.Hour))
+	if !reflect.DeepEqual(g.problems, later.problems) {
+		t.Error("Expected the same problems all day")
+	}
+
+	counts := map[ProblemType]int{}
+	for _, p := range g.problems {
+		counts[p.Type]++
+		if !p.IsCorrect(p.Answer) {
+			t.Errorf("%s: answer %d is not accepted", p.Question, p.Answer)
+		}
+	}
+	for _, problemType := range []ProblemType{Addition, Subtraction, Multiplication, Division} {
+		if counts[problemType] != dailyProblemsPerType {
+			t.Errorf("Expected %d %s problems, got %d", dailyProblemsPerType, problemType, counts[problemType])
+		}
+	}
+
+	if next := NewDailyGenerator(day.AddDate(0, 0, 1)); reflect.DeepEqual(g.problems, next.problems) {
+		t.Error("Expected a different set the next day")
+	}
+}
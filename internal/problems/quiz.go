@@ -0,0 +1,327 @@
+package problems
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Quiz is a fixed list of problems, such as a homework sheet from a teacher
+type Quiz struct {
+	Name     string
+	Problems []Problem
+}
+
+// quizEntry is one problem in a quiz file: either a question with its
+// answer, or two operands and an operator
+type quizEntry struct {
+	Question string     `json:"question"`
+	Answer   quizAnswer `json:"answer"`
+	A        *int       `json:"a"`
+	Operator string     `json:"operator"`
+	B        *int       `json:"b"`
+}
+
+// quizAnswer is an answer as written in a quiz file. JSON quizzes may give
+// it as a number, such as 56, or as a string, such as "6.75".
+type quizAnswer string
+
+// UnmarshalJSON reads an answer written as a JSON number or string, keeping
+// a number as written so its decimal places are kept too
+func (a *quizAnswer) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		*a = quizAnswer(s)
+		return nil
+	}
+
+	var number json.Number
+	if err := json.Unmarshal(data, &number); err != nil {
+		return fmt.Errorf("answer must be a number or a string, got %s", data)
+	}
+	*a = quizAnswer(number)
+	return nil
+}
+
+// quizFile is the JSON form of a quiz
+type quizFile struct {
+	Name     string      `json:"name"`
+	Problems []quizEntry `json:"problems"`
+}
+
+// LoadQuiz reads a quiz from a .json or .csv file. CSV rows are either
+// "question,answer" or "a,operator,b"; a header row is skipped. Quizzes
+// without a name are named after the file.
+func LoadQuiz(path string) (*Quiz, error) {
+	var file quizFile
+	var err error
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		file, err = readQuizJSON(path)
+	case ".csv":
+		file, err = readQuizCSV(path)
+	default:
+		err = fmt.Errorf("unsupported quiz file type %q", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+
+	if file.Name == "" {
+		file.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	quiz := &Quiz{Name: file.Name}
+	problemType := quiz.Type()
+
+	for i, entry := range file.Problems {
+		problem, err := entry.problem(problemType)
+		if err != nil {
+			return nil, fmt.Errorf("%s: problem %d: %w", filepath.Base(path), i+1, err)
+		}
+		quiz.Problems = append(quiz.Problems, problem)
+	}
+
+	if len(quiz.Problems) == 0 {
+		return nil, fmt.Errorf("%s: quiz has no problems", filepath.Base(path))
+	}
+
+	return quiz, nil
+}
+
+// readQuizJSON reads a JSON quiz file
+func readQuizJSON(path string) (quizFile, error) {
+	var file quizFile
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return file, fmt.Errorf("failed to read quiz: %w", err)
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return file, fmt.Errorf("failed to unmarshal quiz: %w", err)
+	}
+	return file, nil
+}
+
+// readQuizCSV reads a CSV quiz file
+func readQuizCSV(path string) (quizFile, error) {
+	var file quizFile
+
+	f, err := os.Open(path)
+	if err != nil {
+		return file, fmt.Errorf("failed to read quiz: %w", err)
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return file, fmt.Errorf("failed to parse quiz: %w", err)
+	}
+
+	for i, row := range rows {
+		switch len(row) {
+		case 2:
+			// Skip a header row like "question,answer"
+			if _, err := strconv.ParseFloat(row[1], 64); err != nil && i == 0 {
+				continue
+			}
+			file.Problems = append(file.Problems, quizEntry{Question: row[0], Answer: quizAnswer(row[1])})
+		case 3:
+			a, errA := strconv.Atoi(row[0])
+			b, errB := strconv.Atoi(row[2])
+			if errA != nil || errB != nil {
+				if i == 0 {
+					continue
+				}
+				return file, fmt.Errorf("row %d: operands must be whole numbers", i+1)
+			}
+			file.Problems = append(file.Problems, quizEntry{A: &a, Operator: row[1], B: &b})
+		default:
+			return file, fmt.Errorf("row %d: expected 2 or 3 columns, got %d", i+1, len(row))
+		}
+	}
+
+	return file, nil
+}
+
+// problem converts a quiz entry into a problem of the given type
+func (e quizEntry) problem(problemType ProblemType) (Problem, error) {
+	if e.A != nil && e.B != nil {
+		operator, ok := customOperators[e.Operator]
+		if !ok {
+			return Problem{}, fmt.Errorf("unknown operator %q", e.Operator)
+		}
+		a, b := *e.A, *e.B
+		if operator.symbol == "÷" && (b == 0 || a%b != 0) {
+			return Problem{}, fmt.Errorf("%d ÷ %d doesn't divide evenly", a, b)
+		}
+
+		return Problem{
+			Question: fmt.Sprintf("%d %s %d", a, operator.symbol, b),
+			Answer:   operator.apply(a, b),
+			Type:     problemType,
+			Operands: []int{a, b},
+		}, nil
+	}
+
+	if e.Question == "" {
+		return Problem{}, fmt.Errorf("needs a question and answer, or a, operator and b")
+	}
+
+	// Answers may be decimals; keep as many places as the answer is written with
+	answer := strings.TrimSpace(string(e.Answer))
+	places := 0
+	if _, fraction, ok := strings.Cut(answer, "."); ok {
+		places = len(fraction)
+	}
+	value, err := ParseDecimal(answer, places)
+	if err != nil {
+		return Problem{}, fmt.Errorf("answer %q is not a number", e.Answer)
+	}
+
+	// "7 × 8 = ?" and "7 × 8" are both asked as "7 × 8 = ?", while questions
+	// like "How many legs do 3 spiders have?" are asked as written
+	question := strings.TrimSpace(e.Question)
+	statement := false
+	switch {
+	case strings.HasSuffix(question, "="), strings.HasSuffix(strings.ReplaceAll(question, " ", ""), "=?"):
+		question = strings.TrimRight(question, "=? ")
+	case strings.HasSuffix(question, "?"):
+		statement = true
+	}
+
+	return Problem{
+		Question:  question,
+		Answer:    value,
+		Type:      problemType,
+		Decimals:  places,
+		Statement: statement,
+	}, nil
+}
+
+// Type returns the problem type results of this quiz are saved under, so
+// each quiz has its own history
+func (q *Quiz) Type() ProblemType {
+	var slug strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(q.Name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if dash && slug.Len() > 0 {
+				slug.WriteRune('-')
+			}
+			slug.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+	return ProblemType("quiz-" + slug.String())
+}
+
+// QuizGenerator serves the problems of a quiz in order or shuffled
+type QuizGenerator struct {
+	quiz    *Quiz
+	shuffle bool
+	order   []int
+	next    int
+	random  *rand.Rand
+}
+
+// NewQuizGenerator creates a generator that serves the problems of quiz,
+// shuffled if shuffle is true
+func NewQuizGenerator(quiz *Quiz, shuffle bool) *QuizGenerator {
+	return &QuizGenerator{
+		quiz:    quiz,
+		shuffle: shuffle,
+		random:  rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Generate returns the next problem of the quiz, starting over once every
+// problem has been served
+func (g *QuizGenerator) Generate() Problem {
+	if g.next == len(g.order) {
+		g.order = make([]int, len(g.quiz.Problems))
+		for i := range g.order {
+			g.order[i] = i
+		}
+		if g.shuffle {
+			g.random.Shuffle(len(g.order), func(i, j int) {
+				g.order[i], g.order[j] = g.order[j], g.order[i]
+			})
+		}
+		g.next = 0
+	}
+
+	problem := g.quiz.Problems[g.order[g.next]]
+	g.next++
+	return problem
+}
+
+// Len returns the number of problems in the quiz
+func (g *QuizGenerator) Len() int {
+	return len(g.quiz.Problems)
+}
+
+// Type returns the type of problems this generator creates
+func (g *QuizGenerator) Type() ProblemType {
+	return g.quiz.Type()
+}
+
+// Name returns a human-readable name for this problem type
+func (g *QuizGenerator) Name() string {
+	return "Quiz: " + g.quiz.Name
+}
+
+// RegisterQuizDir registers a problem type for every .json and .csv quiz
+// in dir. A missing directory is not an error.
+func RegisterQuizDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to list quizzes: %w", err)
+	}
+
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || (ext != ".json" && ext != ".csv") {
+			continue
+		}
+
+		quiz, err := LoadQuiz(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return err
+		}
+		if _, ok := Lookup(quiz.Type()); ok {
+			return fmt.Errorf("%s: a quiz named %q already exists", entry.Name(), quiz.Name)
+		}
+
+		Register(Definition{
+			Type: quiz.Type(),
+			Name: "Quiz: " + quiz.Name,
+			Settings: []Setting{
+				{Key: "shuffle", Description: "problem order", Default: 0, Min: 0, Max: 1,
+					Choices: []string{"In order", "Shuffled"}},
+			},
+			New: func(c Config) (Generator, error) {
+				return NewQuizGenerator(quiz, c["shuffle"] == 1), nil
+			},
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,220 @@
+package problems
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Config holds the settings for a generator, keyed by setting name
+type Config map[string]int
+
+// Setting describes one configurable value of a generator
+type Setting struct {
+	Key         string
+	Description string
+	Default     int
+	Min         int
+	Max         int
+
+	// Choices, if set, names each value from Min upwards. The player picks
+	// one of them before each game instead of it coming from the config.
+	Choices []string
+
+	// Tables marks a set of times tables, stored as made by TablesMask. Like
+	// Choices, the player picks them before each game unless the config
+	// fixes them, and the config file may list them, e.g. [6, 7, 8].
+	Tables bool
+}
+
+// Definition describes a problem type and how to create its generator
+type Definition struct {
+	Type     ProblemType
+	Name     string
+	Settings []Setting
+
+	// New creates a generator from a complete, validated config
+	New func(config Config) (Generator, error)
+
+	// Explain, if set, works through a problem of this type from its
+	// operands, for hints and for feedback on wrong answers
+	Explain func(problem Problem) Explanation
+
+	// Difficulty, if set, rates how hard problems made with a complete
+	// config are, from 1 (easiest) to MaxDifficulty
+	Difficulty func(config Config) int
+
+	// Tiers, if set, are steps of difficulty from easiest to hardest. Each
+	// is unlocked by mastering the one before it.
+	Tiers []Tier
+}
+
+// Tier is a named set of settings for one step of difficulty
+type Tier struct {
+	Name   string
+	Config Config
+}
+
+// MaxDifficulty is the highest difficulty rating a problem type can have
+const MaxDifficulty = 5
+
+// definitions holds every registered problem type in registration order
+var definitions []Definition
+
+// Register adds a problem type to the registry. It panics if the type is
+// already registered, since that is a programming error.
+func Register(def Definition) {
+	if _, ok := Lookup(def.Type); ok {
+		panic(fmt.Sprintf("problems: type %q registered twice", def.Type))
+	}
+	definitions = append(definitions, def)
+}
+
+// Lookup returns the definition for a problem type
+func Lookup(problemType ProblemType) (Definition, bool) {
+	for _, def := range definitions {
+		if def.Type == problemType {
+			return def, true
+		}
+	}
+	return Definition{}, false
+}
+
+// DisplayName returns the name shown for a problem type, which is the type
+// itself if it isn't known
+func DisplayName(problemType ProblemType) string {
+	if problemType == Daily {
+		return DailyName
+	}
+	if def, ok := Lookup(problemType); ok {
+		return def.Name
+	}
+	return string(problemType)
+}
+
+// Definitions returns all registered problem types in registration order
+func Definitions() []Definition {
+	return append([]Definition(nil), definitions...)
+}
+
+// DefaultConfig returns a config with every setting at its default value
+func (d Definition) DefaultConfig() Config {
+	config := Config{}
+	for _, s := range d.Settings {
+		config[s.Key] = s.Default
+	}
+	return config
+}
+
+// Generator creates a generator from config, filling in defaults for any
+// missing settings
+func (d Definition) Generator(config Config) (Generator, error) {
+	complete := d.DefaultConfig()
+	for key, value := range config {
+		complete[key] = value
+	}
+
+	if err := d.Validate(complete); err != nil {
+		return nil, err
+	}
+	return d.New(complete)
+}
+
+// Level returns the difficulty of problems made with config, filling in
+// defaults for any missing settings. Types without a rating are level 1.
+func (d Definition) Level(config Config) int {
+	if d.Difficulty == nil {
+		return 1
+	}
+
+	complete := d.DefaultConfig()
+	for key, value := range config {
+		complete[key] = value
+	}
+	return min(max(d.Difficulty(complete), 1), MaxDifficulty)
+}
+
+// Validate checks that every value in config is a known setting within range
+func (d Definition) Validate(config Config) error {
+	for key, value := range config {
+		s, ok := d.setting(key)
+		if !ok {
+			return fmt.Errorf("%s: unknown setting %q", d.Type, key)
+		}
+		if value < s.Min || value > s.Max {
+			return fmt.Errorf("%s: %s must be between %d and %d", d.Type, key, s.Min, s.Max)
+		}
+	}
+	return nil
+}
+
+// setting returns the setting with the given key
+func (d Definition) setting(key string) (Setting, bool) {
+	for _, s := range d.Settings {
+		if s.Key == key {
+			return s, true
+		}
+	}
+	return Setting{}, false
+}
+
+// LoadConfigFile reads per-type generator settings from a JSON file such as
+// {"addition": {"maxDigits": 3}}. A missing file is not an error.
+func LoadConfigFile(path string) (map[ProblemType]Config, error) {
+	configs := map[ProblemType]Config{}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return configs, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var raw map[ProblemType]map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config file: %w", err)
+	}
+
+	for problemType, values := range raw {
+		def, ok := Lookup(problemType)
+		if !ok {
+			return nil, fmt.Errorf("config file: unknown problem type %q", problemType)
+		}
+
+		config := Config{}
+		for key, value := range values {
+			if config[key], err = def.decodeSetting(key, value); err != nil {
+				return nil, fmt.Errorf("config file: %w", err)
+			}
+		}
+		if err := def.Validate(config); err != nil {
+			return nil, fmt.Errorf("config file: %w", err)
+		}
+		configs[problemType] = config
+	}
+
+	return configs, nil
+}
+
+// decodeSetting reads one setting from the config file. Settings holding
+// times tables may be given as a list.
+func (d Definition) decodeSetting(key string, value json.RawMessage) (int, error) {
+	if s, ok := d.setting(key); ok && s.Tables {
+		var tables []int
+		if err := json.Unmarshal(value, &tables); err == nil {
+			for _, t := range tables {
+				if t < 1 || t > maxTable {
+					return 0, fmt.Errorf("%s: %s must be between 1 and %d", d.Type, key, maxTable)
+				}
+			}
+			return TablesMask(tables), nil
+		}
+	}
+
+	var n int
+	if err := json.Unmarshal(value, &n); err != nil {
+		return 0, fmt.Errorf("%s: %s must be a number", d.Type, key)
+	}
+	return n, nil
+}
@@ -0,0 +1,63 @@
+package problems
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// RoundingGenerator generates "round to the nearest ten/hundred" problems
+type RoundingGenerator struct {
+	min    int
+	max    int
+	random *rand.Rand
+}
+
+// NewRoundingGenerator creates a new rounding problem generator using
+// numbers from min to max, limited to 0 through 9,999,999
+func NewRoundingGenerator(min, max int) *RoundingGenerator {
+	min, max = placeRange(min, max)
+	return &RoundingGenerator{
+		min:    min,
+		max:    max,
+		random: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Generate creates a new rounding problem
+func (g *RoundingGenerator) Generate() Problem {
+	number := randomBetween(g.random, g.min, g.max)
+
+	// Round to a place no bigger than the number itself, starting at tens
+	places := 1
+	for pow10(places+1) <= number && places+1 < len(placeNames) {
+		places++
+	}
+	power := 1 + g.random.Intn(places)
+	place := pow10(power)
+
+	return Problem{
+		Question:  fmt.Sprintf("Round %s to the nearest %s.", FormatThousands(number), placeUnit(power)),
+		Answer:    roundTo(number, place),
+		Type:      Rounding,
+		Operands:  []int{number, place},
+		Statement: true,
+		Thousands: true,
+	}
+}
+
+// placeUnit returns the singular name of a place, e.g. "ten" for tens
+func placeUnit(power int) string {
+	name := placeNames[power]
+	return name[:len(name)-1]
+}
+
+// Type returns the type of problems this generator creates
+func (g *RoundingGenerator) Type() ProblemType {
+	return Rounding
+}
+
+// Name returns a human-readable name for this problem type
+func (g *RoundingGenerator) Name() string {
+	return "Rounding"
+}
@@ -0,0 +1,202 @@
+package problems
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"time"
+)
+
+// maxSampleAttempts is how many times a problem is redrawn to avoid a repeat
+// before the repeat is accepted
+const maxSampleAttempts = 100
+
+// Enumerator is implemented by generators with a small, fixed set of
+// problems, grouped into rows such as the tables of a times table
+type Enumerator interface {
+	// Enumerate returns every problem the generator can create, by row
+	Enumerate() [][]Problem
+}
+
+// SamplingPolicy controls how problems are picked over a session
+type SamplingPolicy struct {
+	// NoRepeats avoids asking the same problem twice
+	NoRepeats bool
+
+	// SameTurnarounds treats problems with the same operands in a different
+	// order, such as 3 × 4 and 4 × 3, as the same problem
+	SameTurnarounds bool
+
+	// Balanced spreads problems evenly over the rows of generators that
+	// implement Enumerator, so every table comes up
+	Balanced bool
+}
+
+// Sampler wraps a generator to apply a sampling policy over a session.
+// Problems from an Enumerator are drawn from shuffled bags, one per row, so
+// every problem comes up once before any repeats; other generators are
+// redrawn until they produce a problem that hasn't been asked yet.
+type Sampler struct {
+	generator Generator
+	policy    SamplingPolicy
+	seen      map[string]bool
+	bags      [][]Problem
+	drawn     []int
+	random    *rand.Rand
+}
+
+// NewSampler creates a sampler that picks problems from generator
+// according to policy
+func NewSampler(generator Generator, policy SamplingPolicy) *Sampler {
+	return &Sampler{
+		generator: generator,
+		policy:    policy,
+		seen:      make(map[string]bool),
+		random:    rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Generate returns the next problem of the session
+func (s *Sampler) Generate() Problem {
+	if enumerator, ok := s.generator.(Enumerator); ok && (s.policy.NoRepeats || s.policy.Balanced) {
+		return s.draw(enumerator)
+	}
+	if !s.policy.NoRepeats {
+		return s.generator.Generate()
+	}
+
+	var problem Problem
+	for i := 0; i < maxSampleAttempts; i++ {
+		problem = s.generator.Generate()
+		if !s.seen[s.key(problem)] {
+			break
+		}
+	}
+	s.seen[s.key(problem)] = true
+	return problem
+}
+
+// draw takes the next problem from the bag of the least-used row, filling
+// the bags again once every problem has been asked
+func (s *Sampler) draw(enumerator Enumerator) Problem {
+	for attempt := 0; attempt < 2; attempt++ {
+		for {
+			row := s.nextRow()
+			if row < 0 {
+				break
+			}
+
+			problem := s.bags[row][0]
+			s.bags[row] = s.bags[row][1:]
+			if s.policy.NoRepeats && s.seen[s.key(problem)] {
+				continue
+			}
+
+			s.seen[s.key(problem)] = true
+			s.drawn[row]++
+			return problem
+		}
+
+		s.fill(enumerator)
+	}
+
+	// Only reached if the generator enumerates no problems at all
+	return s.generator.Generate()
+}
+
+// nextRow picks a row that still has problems in its bag. When balanced,
+// rows asked least often so far are picked first.
+func (s *Sampler) nextRow() int {
+	var candidates []int
+	fewest := -1
+	for row, bag := range s.bags {
+		if len(bag) == 0 {
+			continue
+		}
+		if s.policy.Balanced && fewest >= 0 && s.drawn[row] > fewest {
+			continue
+		}
+		if s.policy.Balanced && (fewest < 0 || s.drawn[row] < fewest) {
+			fewest = s.drawn[row]
+			candidates = candidates[:0]
+		}
+		candidates = append(candidates, row)
+	}
+
+	if len(candidates) == 0 {
+		return -1
+	}
+	return candidates[s.random.Intn(len(candidates))]
+}
+
+// fill shuffles every problem back into the bags and starts a new round
+// in which any problem may be asked again
+func (s *Sampler) fill(enumerator Enumerator) {
+	rows := enumerator.Enumerate()
+
+	// Without balancing, every problem goes in a single bag
+	if !s.policy.Balanced {
+		rows = [][]Problem{slices.Concat(rows...)}
+	}
+
+	s.bags = make([][]Problem, len(rows))
+	for i, row := range rows {
+		bag := slices.Clone(row)
+		s.random.Shuffle(len(bag), func(a, b int) {
+			bag[a], bag[b] = bag[b], bag[a]
+		})
+		s.bags[i] = bag
+	}
+	if len(s.drawn) != len(rows) {
+		s.drawn = make([]int, len(rows))
+	}
+	clear(s.seen)
+}
+
+// key identifies a problem for spotting repeats. The question is part of
+// the key, so 2³ and 3², or 3 + 4 and 3 × 4, are different problems.
+func (s *Sampler) key(problem Problem) string {
+	question, operands := problem.Question, problem.Operands
+	if s.policy.SameTurnarounds {
+		question, operands = turnaround(question, operands)
+	}
+	return fmt.Sprintf("%s:%s:%v", problem.Type, question, operands)
+}
+
+// commutativeOperators are the operators whose operands can swap places
+var commutativeOperators = []string{"+", "×"}
+
+// turnaround puts the operands of a question such as "4 × 3" in order, so
+// it is written the same as "3 × 4". Other questions are left as they are.
+func turnaround(question string, operands []int) (string, []int) {
+	if len(operands) != 2 || operands[0] <= operands[1] {
+		return question, operands
+	}
+
+	a, b := operands[0], operands[1]
+	for _, op := range commutativeOperators {
+		if question == fmt.Sprintf("%d %s %d", a, op, b) {
+			return fmt.Sprintf("%d %s %d", b, op, a), []int{b, a}
+		}
+	}
+	return question, operands
+}
+
+// Seed resets the random source of the sampler and of the generator it
+// wraps, if that can be seeded
+func (s *Sampler) Seed(seed int64) {
+	s.random.Seed(seed)
+	if seeder, ok := s.generator.(Seeder); ok {
+		seeder.Seed(seed)
+	}
+}
+
+// Type returns the type of problems this generator creates
+func (s *Sampler) Type() ProblemType {
+	return s.generator.Type()
+}
+
+// Name returns a human-readable name for this problem type
+func (s *Sampler) Name() string {
+	return s.generator.Name()
+}
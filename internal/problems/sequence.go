@@ -0,0 +1,99 @@
+package problems
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// SequenceGenerator generates "what number is missing" problems from skip
+// counting, counting backwards, times-table and growing patterns
+type SequenceGenerator struct {
+	length int
+	random *rand.Rand
+}
+
+// NewSequenceGenerator creates a new number sequence problem generator
+// showing length terms (4 to 10), one of which is missing
+func NewSequenceGenerator(length int) *SequenceGenerator {
+	return &SequenceGenerator{
+		length: min(max(length, 4), 10),
+		random: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Seed resets the random source so the same seed always produces the same
+// problems, for example so a whole class gets the same set
+func (g *SequenceGenerator) Seed(seed int64) {
+	g.random.Seed(seed)
+}
+
+// Generate creates a new sequence problem
+func (g *SequenceGenerator) Generate() Problem {
+	terms := g.terms()
+	missing := g.random.Intn(len(terms))
+
+	shown := make([]string, len(terms))
+	for i, term := range terms {
+		shown[i] = fmt.Sprintf("%d", term)
+	}
+	shown[missing] = "__"
+
+	return Problem{
+		Question:  fmt.Sprintf("What number is missing? %s", strings.Join(shown, ", ")),
+		Answer:    terms[missing],
+		Type:      Sequence,
+		Operands:  terms,
+		Statement: true,
+	}
+}
+
+// terms builds a random sequence of one of the supported patterns
+func (g *SequenceGenerator) terms() []int {
+	terms := make([]int, g.length)
+
+	switch g.random.Intn(4) {
+	case 0:
+		// Skip counting forwards by 2s, 5s or 10s from a multiple of the step
+		step := []int{2, 5, 10}[g.random.Intn(3)]
+		start := step * g.random.Intn(10)
+		for i := range terms {
+			terms[i] = start + i*step
+		}
+	case 1:
+		// Counting backwards, ending at or above zero
+		step := []int{1, 2, 5, 10}[g.random.Intn(4)]
+		start := step * (g.length - 1 + g.random.Intn(10))
+		for i := range terms {
+			terms[i] = start - i*step
+		}
+	case 2:
+		// A run of the times table for 2 through 12
+		table := g.random.Intn(11) + 2
+		first := g.random.Intn(12-g.length+1) + 1
+		for i := range terms {
+			terms[i] = table * (first + i)
+		}
+	default:
+		// A growing pattern where the gap increases by one each time
+		gap := g.random.Intn(3) + 1
+		terms[0] = g.random.Intn(10) + 1
+		for i := 1; i < len(terms); i++ {
+			terms[i] = terms[i-1] + gap
+			gap++
+		}
+	}
+
+	return terms
+}
+
+// Type returns the type of problems this generator creates
+func (g *SequenceGenerator) Type() ProblemType {
+	return Sequence
+}
+
+// Name returns a human-readable name for this problem type
+func (g *SequenceGenerator) Name() string {
+	return "Number Patterns"
+}
@@ -33,6 +33,7 @@ func (g *SubtractionGenerator) Generate() Problem {
 		Question: fmt.Sprintf("%d - %d", num1, num2),
 		Answer:   num1 - num2,
 		Type:     Subtraction,
+		Operands: []int{num1, num2},
 	}
 }
 
@@ -0,0 +1,114 @@
+package problems
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// maxTable is the largest times table that can be selected
+const maxTable = 20
+
+// TableFocus is implemented by generators that can be limited to some of
+// the times tables
+type TableFocus interface {
+	// Tables returns the tables being practiced, or nil for all of them
+	Tables() []int
+}
+
+// TablesMask encodes a set of times tables as a setting value, with one bit
+// for each table. The empty set, meaning all tables, is 0.
+func TablesMask(tables []int) int {
+	mask := 0
+	for _, t := range tables {
+		mask |= 1 << t
+	}
+	return mask
+}
+
+// MaskTables decodes a setting value made by TablesMask into a sorted list
+// of tables, or nil for all of them
+func MaskTables(mask int) []int {
+	var tables []int
+	for t := 1; t <= maxTable; t++ {
+		if mask&(1<<t) != 0 {
+			tables = append(tables, t)
+		}
+	}
+	return tables
+}
+
+// ParseTables reads a list of times tables such as "6,7,8" or "2-5, 10".
+// An empty list or "all" returns nil.
+func ParseTables(input string) ([]int, error) {
+	input = strings.TrimSpace(input)
+	if input == "" || strings.EqualFold(input, "all") {
+		return nil, nil
+	}
+
+	var tables []int
+	for _, part := range strings.FieldsFunc(input, func(r rune) bool { return r == ',' || r == ' ' }) {
+		first, last, isRange := strings.Cut(part, "-")
+		low, err := strconv.Atoi(first)
+		high := low
+		if err == nil && isRange {
+			high, err = strconv.Atoi(last)
+		}
+		if err != nil || low < 1 || high > maxTable || low > high {
+			return nil, fmt.Errorf("invalid times table %q: use numbers from 1 to %d", part, maxTable)
+		}
+
+		for t := low; t <= high; t++ {
+			if !slices.Contains(tables, t) {
+				tables = append(tables, t)
+			}
+		}
+	}
+
+	slices.Sort(tables)
+	return tables, nil
+}
+
+// FormatTables writes a list of times tables as "6, 7, 8", or "all" for nil
+func FormatTables(tables []int) string {
+	if len(tables) == 0 {
+		return "all"
+	}
+
+	parts := make([]string, len(tables))
+	for i, t := range tables {
+		parts[i] = strconv.Itoa(t)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// checkTables returns an error if any selected table is beyond maxFactor
+func checkTables(tables []int, maxFactor int) error {
+	for _, t := range tables {
+		if t > maxFactor {
+			return fmt.Errorf("the %d times table is beyond the largest factor, %d", t, maxFactor)
+		}
+	}
+	return nil
+}
+
+// FactTables returns the times tables a multiplication or division fact
+// belongs to, such as 6 and 7 for 6 × 7 or 42 ÷ 6, or nil for any other
+// problem
+func FactTables(problem Problem) []int {
+	var factors []int
+	switch {
+	case problem.Type == Multiplication && len(problem.Operands) == 2:
+		factors = problem.Operands
+	case problem.Type == Division && len(problem.Operands) == 2:
+		factors = []int{problem.Operands[1], problem.Answer}
+	default:
+		return nil
+	}
+
+	if factors[0] == factors[1] {
+		return []int{factors[0]}
+	}
+	return []int{factors[0], factors[1]}
+}
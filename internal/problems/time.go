@@ -0,0 +1,117 @@
+package problems
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// minutesPerClock is the number of minutes shown on a 12-hour clock face
+const minutesPerClock = 12 * 60
+
+// elapsedActivities are the events used in elapsed-time problems
+var elapsedActivities = []string{
+	"A movie",
+	"Soccer practice",
+	"A piano lesson",
+	"The bus ride",
+	"A birthday party",
+	"Swimming class",
+}
+
+// TimeGenerator generates telling-time problems from an analog clock and
+// elapsed-time problems. Answers are times of day in H:MM form.
+type TimeGenerator struct {
+	minuteStep int
+	random     *rand.Rand
+}
+
+// NewTimeGenerator creates a new time problem generator. Clock times and
+// durations are multiples of minuteStep minutes.
+func NewTimeGenerator(minuteStep int) *TimeGenerator {
+	return &TimeGenerator{
+		minuteStep: minuteStep,
+		random:     rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Generate creates a new clock-reading or elapsed-time problem
+func (g *TimeGenerator) Generate() Problem {
+	hour := g.random.Intn(12) + 1
+	minute := g.random.Intn(60/g.minuteStep) * g.minuteStep
+	start := hour%12*60 + minute
+
+	if g.random.Intn(2) == 0 {
+		// Clock reading; the UI draws the clock from the operands
+		return Problem{
+			Question:  "What time does the clock show?",
+			Answer:    start,
+			Type:      Time,
+			Kind:      TimeAnswer,
+			Operands:  []int{hour, minute},
+			Statement: true,
+		}
+	}
+
+	duration := (g.random.Intn(120/g.minuteStep) + 1) * g.minuteStep
+	activity := elapsedActivities[g.random.Intn(len(elapsedActivities))]
+
+	return Problem{
+		Question: fmt.Sprintf("%s starts at %s and lasts %s. What time does it end?",
+			activity, FormatClockTime(start), formatMinutes(duration)),
+		Answer:    (start + duration) % minutesPerClock,
+		Type:      Time,
+		Kind:      TimeAnswer,
+		Operands:  []int{hour, minute, duration},
+		Statement: true,
+	}
+}
+
+// Type returns the type of problems this generator creates
+func (g *TimeGenerator) Type() ProblemType {
+	return Time
+}
+
+// Name returns a human-readable name for this problem type
+func (g *TimeGenerator) Name() string {
+	return "Telling Time"
+}
+
+// FormatClockTime formats minutes past 12:00 as H:MM on a 12-hour clock
+func FormatClockTime(minutes int) string {
+	minutes = (minutes%minutesPerClock + minutesPerClock) % minutesPerClock
+	hour := minutes / 60
+	if hour == 0 {
+		hour = 12
+	}
+	return fmt.Sprintf("%d:%02d", hour, minutes%60)
+}
+
+// ParseClockTime parses an H:MM time on a 12-hour clock into minutes past 12:00
+func ParseClockTime(input string) (int, error) {
+	hourText, minuteText, ok := strings.Cut(strings.TrimSpace(input), ":")
+	hour, hourErr := strconv.Atoi(hourText)
+	minute, minuteErr := strconv.Atoi(minuteText)
+	if !ok || len(minuteText) != 2 || hourErr != nil || minuteErr != nil {
+		return 0, fmt.Errorf("invalid input: enter a time like 3:45")
+	}
+	if hour < 1 || hour > 12 || minute < 0 || minute > 59 {
+		return 0, fmt.Errorf("invalid input: %s is not a time on the clock", input)
+	}
+	return hour%12*60 + minute, nil
+}
+
+// formatMinutes describes a duration such as "1 hour 20 minutes"
+func formatMinutes(minutes int) string {
+	hours, minutes := minutes/60, minutes%60
+	switch {
+	case hours == 0:
+		return fmt.Sprintf("%d %s", minutes, Pluralize("minute", minutes))
+	case minutes == 0:
+		return fmt.Sprintf("%d %s", hours, Pluralize("hour", hours))
+	default:
+		return fmt.Sprintf("%d %s %d %s", hours, Pluralize("hour", hours), minutes, Pluralize("minute", minutes))
+	}
+}
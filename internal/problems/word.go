@@ -0,0 +1,254 @@
+package problems
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+)
+
+//go:embed data/word_problems.json
+var defaultWordLibrary []byte
+
+// WordTemplate is a story template for a single operation. Text uses Go
+// text/template syntax with the fields of wordData and the plural function.
+type WordTemplate struct {
+	Operation ProblemType `json:"operation"`
+	Grade     int         `json:"grade"`
+	Text      string      `json:"text"`
+}
+
+// WordLibrary holds story templates along with the pools of names, items
+// and units used to fill them in
+type WordLibrary struct {
+	Names     []string       `json:"names"`
+	Items     []string       `json:"items"`
+	Units     []string       `json:"units"`
+	Templates []WordTemplate `json:"templates"`
+}
+
+// DefaultWordLibrary returns the word problem library bundled with the game
+func DefaultWordLibrary() (*WordLibrary, error) {
+	return parseWordLibrary(defaultWordLibrary)
+}
+
+// LoadWordLibrary reads a word problem library from a JSON file
+func LoadWordLibrary(path string) (*WordLibrary, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read word problem library: %w", err)
+	}
+	return parseWordLibrary(data)
+}
+
+// parseWordLibrary decodes a JSON word problem library
+func parseWordLibrary(data []byte) (*WordLibrary, error) {
+	var library WordLibrary
+	if err := json.Unmarshal(data, &library); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal word problem library: %w", err)
+	}
+
+	// Check every template now, so a broken library fails at startup
+	for i, t := range library.Templates {
+		if _, err := parseWordTemplate(i, t.Text); err != nil {
+			return nil, err
+		}
+	}
+	return &library, nil
+}
+
+// parseWordTemplate parses the text of the i'th template and runs it once
+// with sample data. Parsing alone doesn't catch unknown fields such as
+// {{.Nmae}}, which only fail when the template runs.
+func parseWordTemplate(i int, text string) (*template.Template, error) {
+	parsed, err := template.New(fmt.Sprintf("template %d", i+1)).
+		Funcs(template.FuncMap{"plural": Pluralize}).
+		Option("missingkey=error").
+		Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse word problem template: %w", err)
+	}
+
+	sample := wordData{Name: "Maya", Name2: "Leo", Item: "apple", Unit: "cm", A: 12, B: 3}
+	if err := parsed.Execute(io.Discard, sample); err != nil {
+		return nil, fmt.Errorf("failed to run word problem template: %w", err)
+	}
+	return parsed, nil
+}
+
+// Merge adds the pools and templates of other to the library
+func (l *WordLibrary) Merge(other *WordLibrary) {
+	l.Names = append(l.Names, other.Names...)
+	l.Items = append(l.Items, other.Items...)
+	l.Units = append(l.Units, other.Units...)
+	l.Templates = append(l.Templates, other.Templates...)
+}
+
+// wordData is the data available to a story template
+type wordData struct {
+	Name  string
+	Name2 string
+	Item  string
+	Unit  string
+	A     int
+	B     int
+}
+
+// wordStory is a parsed template ready to be filled in
+type wordStory struct {
+	operation ProblemType
+	text      *template.Template
+}
+
+// WordProblemGenerator generates word problems by filling story templates
+// with numbers from the arithmetic generators
+type WordProblemGenerator struct {
+	library    *WordLibrary
+	stories    []wordStory
+	generators map[ProblemType]Generator
+	random     *rand.Rand
+}
+
+// NewWordProblemGenerator creates a new word problem generator using the
+// templates in library for the given grade and below
+func NewWordProblemGenerator(library *WordLibrary, grade int) (*WordProblemGenerator, error) {
+	if len(library.Names) < 2 || len(library.Items) == 0 || len(library.Units) == 0 {
+		return nil, fmt.Errorf("word problem library needs at least two names, one item and one unit")
+	}
+
+	generators := map[ProblemType]Generator{
+		Addition:       NewAdditionGenerator(2),
+		Subtraction:    NewSubtractionGenerator(2),
+		Multiplication: NewMultiplicationGenerator(10),
+		Division:       NewDivisionGenerator(10),
+	}
+
+	var stories []wordStory
+	for i, t := range library.Templates {
+		if t.Grade > grade {
+			continue
+		}
+		if _, ok := generators[t.Operation]; !ok {
+			return nil, fmt.Errorf("template %d: unsupported operation %q", i+1, t.Operation)
+		}
+
+		text, err := parseWordTemplate(i, t.Text)
+		if err != nil {
+			return nil, err
+		}
+		stories = append(stories, wordStory{operation: t.Operation, text: text})
+	}
+
+	if len(stories) == 0 {
+		return nil, fmt.Errorf("no word problem templates for grade %d", grade)
+	}
+
+	return &WordProblemGenerator{
+		library:    library,
+		stories:    stories,
+		generators: generators,
+		random:     rand.New(rand.NewSource(time.Now().UnixNano())),
+	}, nil
+}
+
+// Generate creates a new word problem
+func (g *WordProblemGenerator) Generate() Problem {
+	story := g.stories[g.random.Intn(len(g.stories))]
+	problem := g.generators[story.operation].Generate()
+
+	// Pick two different names
+	names := g.library.Names
+	first := g.random.Intn(len(names))
+	second := (first + 1 + g.random.Intn(len(names)-1)) % len(names)
+
+	data := wordData{
+		Name:  names[first],
+		Name2: names[second],
+		Item:  g.library.Items[g.random.Intn(len(g.library.Items))],
+		Unit:  g.library.Units[g.random.Intn(len(g.library.Units))],
+		A:     problem.Operands[0],
+		B:     problem.Operands[1],
+	}
+
+	var question strings.Builder
+	if err := story.text.Execute(&question, data); err != nil {
+		// Every template ran once when the generator was created, so this
+		// shouldn't happen; if it does, the plain problem can still be asked
+		return problem
+	}
+
+	return Problem{
+		Question:  question.String(),
+		Answer:    problem.Answer,
+		Type:      WordProblem,
+		Operands:  problem.Operands,
+		Statement: true,
+	}
+}
+
+// Type returns the type of problems this generator creates
+func (g *WordProblemGenerator) Type() ProblemType {
+	return WordProblem
+}
+
+// Name returns a human-readable name for this problem type
+func (g *WordProblemGenerator) Name() string {
+	return "Word Problems"
+}
+
+// irregularPlurals lists nouns that don't follow the usual English rules
+var irregularPlurals = map[string]string{
+	"child":  "children",
+	"person": "people",
+	"mouse":  "mice",
+	"goose":  "geese",
+	"foot":   "feet",
+	"tooth":  "teeth",
+	"man":    "men",
+	"woman":  "women",
+	"leaf":   "leaves",
+	"loaf":   "loaves",
+	"knife":  "knives",
+	"shelf":  "shelves",
+	"wolf":   "wolves",
+	"sheep":  "sheep",
+	"fish":   "fish",
+	"deer":   "deer",
+	"potato": "potatoes",
+	"tomato": "tomatoes",
+}
+
+// Pluralize returns the form of noun that agrees with count. In multi-word
+// nouns the last word is pluralized ("toy buses"), except for "X of Y"
+// phrases where X is ("boxes of raisins").
+func Pluralize(noun string, count int) string {
+	if count == 1 || count == -1 || noun == "" {
+		return noun
+	}
+
+	if head, rest, ok := strings.Cut(noun, " of "); ok {
+		return Pluralize(head, count) + " of " + rest
+	}
+	if i := strings.LastIndex(noun, " "); i >= 0 {
+		return noun[:i+1] + Pluralize(noun[i+1:], count)
+	}
+
+	if plural, ok := irregularPlurals[noun]; ok {
+		return plural
+	}
+
+	switch {
+	case strings.HasSuffix(noun, "y") && len(noun) > 1 && !strings.ContainsRune("aeiou", rune(noun[len(noun)-2])):
+		return noun[:len(noun)-1] + "ies"
+	case strings.HasSuffix(noun, "s"), strings.HasSuffix(noun, "x"), strings.HasSuffix(noun, "z"),
+		strings.HasSuffix(noun, "ch"), strings.HasSuffix(noun, "sh"):
+		return noun + "es"
+	default:
+		return noun + "s"
+	}
+}
@@ -0,0 +1,94 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"math-game/internal/problems"
+)
+
+// Clock face dimensions. Terminal cells are about twice as tall as they are
+// wide, so the face is stretched horizontally to look round.
+const (
+	clockRows    = 13
+	clockCols    = 27
+	clockRadiusY = 6.0
+	clockRadiusX = 12.0
+)
+
+// renderClockProblem draws the clock for clock-reading time problems.
+// Elapsed-time problems carry a duration operand and need no picture.
+func renderClockProblem(problem problems.Problem) string {
+	if len(problem.Operands) != 2 {
+		return ""
+	}
+	return RenderClock(problem.Operands[0], problem.Operands[1])
+}
+
+// RenderClock draws an analog clock face showing the given time
+func RenderClock(hour, minute int) string {
+	grid := make([][]rune, clockRows)
+	for i := range grid {
+		grid[i] = []rune(strings.Repeat(" ", clockCols))
+	}
+
+	centerX, centerY := clockCols/2, clockRows/2
+
+	// plot places a rune at a point given as an angle clockwise from 12
+	// and a fraction of the radius
+	plot := func(angle, length float64, r rune) (int, int) {
+		x := centerX + int(math.Round(math.Sin(angle)*length*clockRadiusX))
+		y := centerY - int(math.Round(math.Cos(angle)*length*clockRadiusY))
+		grid[y][x] = r
+		return x, y
+	}
+
+	// Draw the rim and the numbers
+	for step := 0; step < 120; step++ {
+		plot(float64(step)/120*2*math.Pi, 1.0, '.')
+	}
+	for n := 1; n <= 12; n++ {
+		label := []rune(fmt.Sprintf("%d", n))
+		x, y := plot(float64(n)/12*2*math.Pi, 0.82, label[0])
+		if len(label) > 1 {
+			grid[y][x+1] = label[1]
+		}
+	}
+
+	// Draw the long minute hand and the short hour hand
+	minuteAngle := float64(minute) / 60 * 2 * math.Pi
+	hourAngle := (float64(hour%12) + float64(minute)/60) / 12 * 2 * math.Pi
+	drawHand := func(angle, length float64) {
+		for t := 0.1; t <= length; t += 0.05 {
+			plot(angle, t, handRune(angle))
+		}
+	}
+	drawHand(minuteAngle, 0.65)
+	drawHand(hourAngle, 0.4)
+	grid[centerY][centerX] = 'o'
+
+	var b strings.Builder
+	for _, row := range grid {
+		b.WriteString(strings.TrimRight(string(row), " "))
+		b.WriteString("\n")
+	}
+	b.WriteString("(short hand = hour, long hand = minutes)\n")
+	return b.String()
+}
+
+// handRune picks the character that best follows a hand's direction
+func handRune(angle float64) rune {
+	// Fold the angle into [0, pi) since a line looks the same both ways
+	a := math.Mod(angle, math.Pi)
+	switch {
+	case a < math.Pi/8 || a >= 7*math.Pi/8:
+		return '|'
+	case a < 3*math.Pi/8:
+		return '/'
+	case a < 5*math.Pi/8:
+		return '-'
+	default:
+		return '\\'
+	}
+}
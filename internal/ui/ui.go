@@ -2,46 +2,107 @@ package ui
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
 	"time"
 
+	"math-game/internal/achievements"
 	"math-game/internal/game"
 	"math-game/internal/problems"
 )
 
+// ErrHintRequested is returned by DisplayProblem when the player asks for a
+// hint instead of answering
+var ErrHintRequested = errors.New("hint requested")
+
 // UI represents the user interface for the game
 type UI interface {
 	// ShowMenu displays the main menu and returns the selected option
 	ShowMenu(options []string) (int, error)
 
-	// DisplayProblem shows a problem to the user and gets their answer
+	// DisplayProblem shows a problem to the user and gets their answer. It
+	// returns ErrHintRequested if the user asks for a hint instead.
 	DisplayProblem(problem problems.Problem, problemNum, total int) (int, error)
 
+	// DisplayStep asks for one intermediate step of a problem and gets the answer
+	DisplayStep(step problems.Problem, stepNum, totalSteps int) (int, error)
+
 	// ShowResults displays the results of a completed game session
 	ShowResults(result game.Result)
 
 	// ShowHistory displays historical game results
 	ShowHistory(results []game.Result)
 
+	// ShowTrophyCase displays every badge, unlocked or not
+	ShowTrophyCase(badges []achievements.Badge)
+
 	// ShowMessage displays a message to the user
 	ShowMessage(message string)
 
+	// Ask shows a prompt and returns the line of text the user types
+	Ask(prompt string) (string, error)
+
 	// Clear clears the screen
 	Clear()
+
+	// RegisterRenderer sets a renderer used to draw problems of the given type
+	RegisterRenderer(problemType problems.ProblemType, renderer Renderer)
 }
 
+// Renderer draws a picture for a problem, such as a clock face, which is
+// shown above the question in place of the problem's own Figure. It returns
+// an empty string if there is nothing to draw.
+type Renderer func(problem problems.Problem) string
+
 // TerminalUI implements a simple terminal-based UI
 type TerminalUI struct {
-	reader *bufio.Reader
+	reader    *bufio.Reader
+	renderers map[problems.ProblemType]Renderer
+	ascii     bool
 }
 
 // NewTerminalUI creates a new terminal UI
 func NewTerminalUI() *TerminalUI {
 	return &TerminalUI{
 		reader: bufio.NewReader(os.Stdin),
+		renderers: map[problems.ProblemType]Renderer{
+			problems.Time: renderClockProblem,
+		},
+		ascii: !unicodeSupported(),
+	}
+}
+
+// SetASCII chooses whether math symbols such as ² and ÷ are written in
+// plain ASCII, overriding what was detected from the locale
+func (ui *TerminalUI) SetASCII(ascii bool) {
+	ui.ascii = ascii
+}
+
+// unicodeSupported guesses from the locale whether the terminal can show
+// characters such as ² and ÷
+func unicodeSupported() bool {
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if value := strings.ToUpper(os.Getenv(name)); value != "" {
+			return strings.Contains(value, "UTF-8") || strings.Contains(value, "UTF8")
+		}
+	}
+	return false
+}
+
+// text prepares text for the terminal, falling back to ASCII if needed
+func (ui *TerminalUI) text(s string) string {
+	if ui.ascii {
+		return problems.ASCII(s)
 	}
+	return s
+}
+
+// RegisterRenderer sets a renderer used to draw problems of the given type,
+// replacing any existing renderer for that type
+func (ui *TerminalUI) RegisterRenderer(problemType problems.ProblemType, renderer Renderer) {
+	ui.renderers[problemType] = renderer
 }
 
 // readInput reads a line of input from the user
@@ -60,7 +121,13 @@ func (ui *TerminalUI) Clear() {
 
 // ShowMessage displays a message to the user
 func (ui *TerminalUI) ShowMessage(message string) {
-	fmt.Println(message)
+	fmt.Println(ui.text(message))
+}
+
+// Ask shows a prompt and returns the line of text the user types
+func (ui *TerminalUI) Ask(prompt string) (string, error) {
+	fmt.Printf("%s ", ui.text(prompt))
+	return ui.readInput()
 }
 
 // ShowMenu displays the main menu and returns the selected option
@@ -91,19 +158,34 @@ func (ui *TerminalUI) ShowMenu(options []string) (int, error) {
 // DisplayProblem shows a problem to the user and gets their answer
 func (ui *TerminalUI) DisplayProblem(problem problems.Problem, problemNum, total int) (int, error) {
 	fmt.Printf("\nProblem %d of %d:\n", problemNum, total)
-	fmt.Printf("%s = ? ", problem.Question)
+	if render, ok := ui.renderers[problem.Type]; ok {
+		fmt.Print(ui.text(render(problem)))
+	} else if problem.Figure != "" {
+		fmt.Print(ui.text(problem.Figure))
+	}
+	fmt.Printf("%s ", ui.text(problem.Prompt()))
 
 	input, err := ui.readInput()
 	if err != nil {
 		return 0, err
 	}
+	if input == "?" || strings.EqualFold(input, "hint") {
+		return 0, ErrHintRequested
+	}
 
-	var answer int
-	if _, err := fmt.Sscanf(input, "%d", &answer); err != nil {
-		return 0, fmt.Errorf("invalid input: %w", err)
+	return problem.ParseAnswer(input)
+}
+
+// DisplayStep asks for one intermediate step of a problem and gets the answer
+func (ui *TerminalUI) DisplayStep(step problems.Problem, stepNum, totalSteps int) (int, error) {
+	fmt.Printf("  Step %d of %d: %s ", stepNum, totalSteps, ui.text(step.Prompt()))
+
+	input, err := ui.readInput()
+	if err != nil {
+		return 0, err
 	}
 
-	return answer, nil
+	return step.ParseAnswer(input)
 }
 
 // ShowResults displays the results of a completed game session
@@ -111,16 +193,65 @@ func (ui *TerminalUI) ShowResults(result game.Result) {
 	ui.Clear()
 	fmt.Println("Game Results:")
 	fmt.Println("-------------")
-	fmt.Printf("Game Type: %s\n", result.ProblemType)
+	fmt.Printf("Game Type: %s\n", problems.DisplayName(result.ProblemType))
+	if len(result.Tables) > 0 {
+		fmt.Printf("Tables: %s\n", problems.FormatTables(result.Tables))
+	}
 	fmt.Printf("Score: %d / %d (%.1f%%)\n",
 		result.CorrectCount,
 		result.TotalCount,
 		result.PercentCorrect())
+	if result.EventuallyCorrect > result.CorrectCount {
+		fmt.Printf("Right after retries: %d / %d\n", result.EventuallyCorrect, result.TotalCount)
+	}
+	if result.ScoringVersion != "" {
+		fmt.Printf("You earned %s\n", formatPoints(result))
+		fmt.Printf("Best streak: %d\n", result.BestStreak)
+	}
 	fmt.Printf("Time: %s\n", formatDuration(result.Duration))
+	if result.HintsUsed > 0 {
+		fmt.Printf("Hints used: %d\n", result.HintsUsed)
+	}
+	if len(result.TableScores) > 0 {
+		fmt.Println("\nBy times table:")
+		showTableScores(result.TableScores)
+	}
+	if result.Level > 0 {
+		fmt.Printf("+%d XP - Level %d\n", result.XP, result.Level)
+	}
+	if result.LevelUp {
+		fmt.Printf("\n*** Level up! You reached level %d! ***\n", result.Level)
+	}
+	for _, tier := range result.Unlocks {
+		fmt.Printf("\n*** New difficulty unlocked: %s! ***\n", tier)
+	}
+	for _, badge := range result.Badges {
+		fmt.Printf("\n*** New badge unlocked: %s! ***\n", badge)
+	}
+	for _, goal := range result.GoalsMet {
+		fmt.Printf("\n*** Goal reached: %s! ***\n", goal)
+	}
 	fmt.Println("\nPress Enter to continue...")
 	ui.readInput()
 }
 
+// showTableScores prints how many facts of each times table were answered
+// correctly at the first try
+func showTableScores(scores []game.TableScore) {
+	for _, score := range scores {
+		fmt.Printf("  %2ds: %d / %d (%.1f%%)\n", score.Table, score.Correct, score.Total, score.Percent())
+	}
+}
+
+// formatPoints formats a result's points, naming the scoring rules if they
+// aren't the current ones so old and new scores aren't confused
+func formatPoints(result game.Result) string {
+	if result.ScoringVersion != game.DefaultScoring {
+		return fmt.Sprintf("%d points (%s rules)", result.Score, result.ScoringVersion)
+	}
+	return fmt.Sprintf("%d points", result.Score)
+}
+
 // formatDuration formats a duration as MM:SS
 func formatDuration(d time.Duration) string {
 	seconds := int(d.Seconds())
@@ -140,18 +271,55 @@ func (ui *TerminalUI) ShowHistory(results []game.Result) {
 		return
 	}
 
-	fmt.Printf("History for %s:\n", results[0].ProblemType)
+	fmt.Printf("History for %s:\n", problems.DisplayName(results[0].ProblemType))
 	fmt.Println("-------------------")
 
 	for i, result := range results {
-		fmt.Printf("%d. Score: %d/%d (%.1f%%) - Time: %s - %s\n",
+		fmt.Printf("%d. Score: %d/%d (%.1f%%) - Time: %s - %s",
 			i+1,
 			result.CorrectCount,
 			result.TotalCount,
 			result.PercentCorrect(),
 			formatDuration(result.Duration),
 			result.CompletionTime.Format("Jan 02, 2006 15:04"))
+		if result.EventuallyCorrect > result.CorrectCount {
+			fmt.Printf(" - %d after retries", result.EventuallyCorrect)
+		}
+		if result.ScoringVersion != "" {
+			fmt.Printf(" - %s", formatPoints(result))
+		}
+		if len(result.Tables) > 0 {
+			fmt.Printf(" - Tables: %s", problems.FormatTables(result.Tables))
+		}
+		fmt.Println()
+	}
+
+	// Add up each times table over the sessions shown
+	if scores := game.MergeTableScores(results); len(scores) > 0 {
+		fmt.Println("\nBy times table, over these sessions:")
+		showTableScores(scores)
+	}
+
+	fmt.Println("\nPress Enter to continue...")
+	ui.readInput()
+}
+
+// ShowTrophyCase displays every badge, unlocked or not
+func (ui *TerminalUI) ShowTrophyCase(badges []achievements.Badge) {
+	ui.Clear()
+	fmt.Println("Trophy Case:")
+	fmt.Println("------------")
+
+	unlocked := 0
+	for _, badge := range badges {
+		if badge.Unlocked.IsZero() {
+			fmt.Printf("[ ] %s - %s\n", badge.Name, badge.Description)
+			continue
+		}
+		unlocked++
+		fmt.Printf("[*] %s - %s (%s)\n", badge.Name, badge.Description, badge.Unlocked.Format("Jan 02, 2006"))
 	}
+	fmt.Printf("\n%d of %d badges unlocked\n", unlocked, len(badges))
 
 	fmt.Println("\nPress Enter to continue...")
 	ui.readInput()